@@ -0,0 +1,84 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bech32Prefix is the human readable part of evmos account addresses
+const Bech32Prefix = "evmos"
+
+var (
+	// ErrInvalidAddress is returned for input that is neither a 0x hex nor an evmos bech32 address
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrChecksum is returned when a mixed case hex address or a bech32 address fails its checksum
+	ErrChecksum = errors.New("address checksum mismatch")
+)
+
+// Parse parses a 0x prefixed hex address or an `evmos1...` bech32 address. Mixed case hex addresses must carry a
+// valid EIP-55 checksum, all lower or all upper case ones are accepted as is.
+func Parse(s string) (common.Address, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return parseHex(s)
+	}
+
+	if strings.HasPrefix(strings.ToLower(s), Bech32Prefix+"1") {
+		return FromBech32(s)
+	}
+
+	return common.Address{}, fmt.Errorf("%w %q: expected 0x hex or %s1 bech32", ErrInvalidAddress, s, Bech32Prefix)
+}
+
+// parseHex parses a 0x prefixed hex address, verifying the EIP-55 checksum of mixed case input.
+func parseHex(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%w %q", ErrInvalidAddress, s)
+	}
+
+	addr := common.HexToAddress(s)
+
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex()[2:] != digits {
+		return common.Address{}, fmt.Errorf("%w %q, expected %s", ErrChecksum, s, addr.Hex())
+	}
+
+	return addr, nil
+}
+
+// FromBech32 parses an evmos bech32 address.
+func FromBech32(s string) (common.Address, error) {
+	hrp, data, err := bech32Decode(s)
+	if errors.Is(err, errBadChecksum) {
+		return common.Address{}, fmt.Errorf("%w %q", ErrChecksum, s)
+	} else if err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: %v", ErrInvalidAddress, s, err)
+	}
+
+	if hrp != Bech32Prefix {
+		return common.Address{}, fmt.Errorf("%w %q: prefix %q is not %q", ErrInvalidAddress, s, hrp, Bech32Prefix)
+	}
+
+	raw, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: %v", ErrInvalidAddress, s, err)
+	}
+
+	if len(raw) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w %q: %d bytes long", ErrInvalidAddress, s, len(raw))
+	}
+
+	return common.BytesToAddress(raw), nil
+}
+
+// ToBech32 returns the evmos bech32 form of the address.
+func ToBech32(addr common.Address) string {
+	// regrouping whole bytes into 5 bit groups with padding cannot fail
+	data, _ := convertBits(addr.Bytes(), 8, 5, true)
+
+	return bech32Encode(Bech32Prefix, data)
+}
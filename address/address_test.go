@@ -0,0 +1,109 @@
+package address_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/address"
+)
+
+var (
+	testAddr   = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testBech32 = "evmos17w0adeg64ky0daxwd2ugyuneellmjgnxpu2u3g"
+)
+
+func TestParse(t *testing.T) {
+	subtests := []struct {
+		name    string
+		input   string
+		want    common.Address
+		wantErr error
+	}{
+		{
+			name:  "Checksummed hex address",
+			input: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			want:  testAddr,
+		},
+		{
+			name:  "Lower case hex address",
+			input: "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266",
+			want:  testAddr,
+		},
+		{
+			name:  "Upper case hex address",
+			input: "0xF39FD6E51AAD88F6F4CE6AB8827279CFFFB92266",
+			want:  testAddr,
+		},
+		{
+			name:  "Bech32 address",
+			input: testBech32,
+			want:  testAddr,
+		},
+		{
+			name:  "Upper case bech32 address",
+			input: strings.ToUpper(testBech32),
+			want:  testAddr,
+		},
+		{
+			name:    "Error hex checksum",
+			input:   "0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			wantErr: address.ErrChecksum,
+		},
+		{
+			name:    "Error bech32 checksum",
+			input:   "evmos17w0adeg64ky0daxwd2ugyuneellmjgnxpu2u3h",
+			wantErr: address.ErrChecksum,
+		},
+		{
+			name:    "Error short hex address",
+			input:   "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb922",
+			wantErr: address.ErrInvalidAddress,
+		},
+		{
+			name:    "Error hex address without prefix",
+			input:   "f39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			wantErr: address.ErrInvalidAddress,
+		},
+		{
+			name:    "Error mixed case bech32 address",
+			input:   "evmos17W0adeg64ky0daxwd2ugyuneellmjgnxpu2u3g",
+			wantErr: address.ErrInvalidAddress,
+		},
+		{
+			name:    "Error other bech32 prefix",
+			input:   "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a",
+			wantErr: address.ErrInvalidAddress,
+		},
+		{
+			name:    "Error empty address",
+			input:   "",
+			wantErr: address.ErrInvalidAddress,
+		},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := address.Parse(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, addr)
+		})
+	}
+}
+
+func TestToBech32(t *testing.T) {
+	assert.Equal(t, testBech32, address.ToBech32(testAddr))
+	assert.Equal(t, "evmos1wzvhjux9rqfdcwspp37srdgwp5tac7wgfhwr9w", address.ToBech32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")))
+
+	// round trip
+	addr, err := address.FromBech32(address.ToBech32(testAddr))
+	assert.NoError(t, err)
+	assert.Equal(t, testAddr, addr)
+}
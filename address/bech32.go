@@ -0,0 +1,147 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// charset is the bech32 alphabet, the index of a character is its 5 bit value
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// checksumLength is the number of 5 bit groups of the bech32 checksum
+const checksumLength = 6
+
+var (
+	errMixedCase   = errors.New("bech32 string mixes upper and lower case")
+	errNoSeparator = errors.New("bech32 string has no separator")
+	errBadChecksum = errors.New("bech32 checksum mismatch")
+)
+
+// polymod computes the BCH checksum over the 5 bit values as defined in BIP-173.
+func polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// hrpExpand spreads the human readable part over 5 bit values for checksum computation.
+func hrpExpand(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+
+	return values
+}
+
+// bech32Encode encodes the 5 bit data under the human readable part, with its checksum appended.
+func bech32Encode(hrp string, data []byte) string {
+	values := append(hrpExpand(hrp), data...)
+	mod := polymod(append(values, make([]byte, checksumLength)...)) ^ 1
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+
+	for _, v := range data {
+		b.WriteByte(charset[v])
+	}
+
+	for i := 0; i < checksumLength; i++ {
+		b.WriteByte(charset[(mod>>uint(5*(5-i)))&31])
+	}
+
+	return b.String()
+}
+
+// bech32Decode splits a bech32 string into its human readable part and 5 bit data, the checksum is verified
+// and stripped.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errMixedCase
+	}
+
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+checksumLength+1 > len(s) {
+		return "", nil, errNoSeparator
+	}
+
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid bech32 prefix character %q", hrp[i])
+		}
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, r := range s[sep+1:] {
+		v := strings.IndexRune(charset, r)
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character %q", r)
+		}
+
+		data = append(data, byte(v))
+	}
+
+	if polymod(append(hrpExpand(hrp), data...)) != 1 {
+		return "", nil, errBadChecksum
+	}
+
+	return hrp, data[:len(data)-checksumLength], nil
+}
+
+// convertBits regroups the bits of data from groups of `from` bits into groups of `to` bits. With pad set the
+// last group is zero padded, otherwise left over bits must be zero padding.
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint
+		bits uint
+		out  []byte
+	)
+
+	maxv := uint(1)<<to - 1
+
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, fmt.Errorf("invalid %d bit value %d", from, v)
+		}
+
+		acc = acc<<from | uint(v)
+		bits += from
+
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid bech32 padding")
+	}
+
+	return out, nil
+}
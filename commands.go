@@ -0,0 +1,2416 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/gopherine/evmos-conploy/address"
+	"github.com/gopherine/evmos-conploy/compiler"
+	"github.com/gopherine/evmos-conploy/config"
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/index"
+	"github.com/gopherine/evmos-conploy/manifest"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// Exit codes returned by the cli, anything that is not wrapped in a `cli.ExitCoder` by a command action
+// comes from argument parsing and is reported as a usage error.
+const (
+	exitFailure               = 1
+	exitUsage                 = 2
+	exitInvalidAddress        = 3
+	exitZeroAddress           = 4
+	exitInvalidAmount         = 5
+	exitNegativeAmount        = 6
+	exitInsufficientBalance   = 7
+	exitInsufficientAllowance = 8
+	exitChainMismatch         = 9
+)
+
+// Address formats accepted by the global `--address-format` flag
+const (
+	formatHex    = "hex"
+	formatBech32 = "bech32"
+	formatBoth   = "both"
+)
+
+// Output formats of commands printing structured results
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+	outputCSV   = "csv"
+)
+
+// newApp wires every subcommand to the given contract module. Commands talking to the node have connect configure
+// the module for the selected network right before their action runs, so help and offline commands need neither a
+// node nor any configuration. Offline commands signing transactions load the signer of the selected network with
+// signer.
+func newApp(c *contract.Contract, connect func(*cli.Context, *contract.Contract) (func(), error), signer func(*cli.Context) (contract.Signer, error)) *cli.App {
+	online := []*cli.Command{
+		deployCommand(c),
+		receiptCommand(c),
+		deploymentsCommand(c),
+		transferCommand(c),
+		balanceCommand(c),
+		approveCommand(c),
+		allowanceCommand(c),
+		transferFromCommand(c),
+		distributeCommand(c),
+		increaseAllowanceCommand(c),
+		decreaseAllowanceCommand(c),
+		infoCommand(c),
+		historyCommand(c),
+		watchCommand(c),
+		indexCommand(c),
+		callCommand(c),
+		sendCommand(c),
+		applyCommand(c),
+		buildTxCommand(c),
+		broadcastCommand(c),
+		speedUpCommand(c),
+		cancelCommand(c),
+	}
+
+	offline := []*cli.Command{
+		compileCommand(),
+		precomputeCommand(),
+		addressCommand(),
+		signCommand(signer),
+	}
+
+	var connected func(cmd *cli.Command)
+	connected = func(cmd *cli.Command) {
+		for _, sub := range cmd.Subcommands {
+			connected(sub)
+		}
+
+		if cmd.Action == nil {
+			return
+		}
+
+		action := cmd.Action
+		cmd.Action = func(cCtx *cli.Context) error {
+			closeAll, err := connect(cCtx, c)
+			if err != nil {
+				return err
+			}
+			// released whether the command succeeds or not, before the error reaches main
+			defer closeAll()
+
+			return action(cCtx)
+		}
+	}
+
+	for _, cmd := range online {
+		connected(cmd)
+	}
+
+	commands := append(online, offline...)
+
+	// every input is passed through named flags, stray positional arguments are most likely a
+	// mistyped flag and must not be silently ignored, subcommands check their own arguments
+	for _, cmd := range commands {
+		if cmd.Before == nil && len(cmd.Subcommands) == 0 {
+			cmd.Before = rejectArgs
+		}
+	}
+
+	// cancels the `--timeout` deadline once the command is done
+	cancel := func() {}
+
+	return &cli.App{
+		Name:           "conploy",
+		Usage:          "Deploy and interact with smart contracts on an evmos node",
+		ExitErrHandler: handleExitErr,
+		Commands:       commands,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "abort the command after `DURATION`, no limit when zero",
+			},
+			&cli.StringFlag{
+				Name:  "address-format",
+				Usage: "print addresses as `FORMAT`, one of hex, bech32 or both",
+				Value: formatHex,
+			},
+			&cli.StringFlag{
+				Name:    "network",
+				Usage:   "`NAME` of the network profile to use, eg. local, testnet or mainnet, defaults to the network of the config file or the CLIENT_URL of .env",
+				EnvVars: []string{"CONPLOY_NETWORK"},
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "`FILE` with the network profiles, optional unless given",
+				EnvVars: []string{"CONPLOY_CONFIG"},
+				Value:   config.DefaultPath,
+			},
+			&cli.StringFlag{
+				Name:  "rpc",
+				Usage: "`URL` of the node, overrides the rpc urls of the network profile",
+			},
+			&cli.StringFlag{
+				Name:    "registry",
+				Usage:   "`DIR` of the local deployment registry",
+				EnvVars: []string{"REGISTRY_PATH"},
+				Value:   registry.DefaultPath,
+			},
+			&cli.StringFlag{
+				Name:    "index",
+				Usage:   "`DIR` of the local event index",
+				EnvVars: []string{"INDEX_PATH"},
+				Value:   index.DefaultPath,
+			},
+		},
+		Before: func(cCtx *cli.Context) error {
+			switch format := cCtx.String("address-format"); format {
+			case formatHex, formatBech32, formatBoth:
+			default:
+				return usageError("invalid address format %q, expected hex, bech32 or both", format)
+			}
+
+			if timeout := cCtx.Duration("timeout"); timeout > 0 {
+				cCtx.Context, cancel = context.WithTimeout(cCtx.Context, timeout)
+			}
+
+			return nil
+		},
+		After: func(cCtx *cli.Context) error {
+			cancel()
+			return nil
+		},
+	}
+}
+
+// handleExitErr replaces the default handler of urfave/cli, which exits as soon as an action fails and skips the
+// deferred cleanups of the command along with `After`. The error is returned by `RunContext` instead, main exits
+// with its `exitStatus`.
+func handleExitErr(_ *cli.Context, _ error) {}
+
+// exitStatus returns the code the cli exits with for the error that ended a command, the code of a `cli.ExitCoder`
+// and `exitUsage` for anything else, which comes from argument parsing.
+func exitStatus(err error) int {
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return exitUsage
+}
+
+// rejectArgs fails the command when positional arguments are given.
+func rejectArgs(cCtx *cli.Context) error {
+	if cCtx.NArg() > 0 {
+		return usageError("unexpected arguments %v, use named flags instead (see --help)", cCtx.Args().Slice())
+	}
+
+	return nil
+}
+
+// failure wraps an error returned by the contract module so the cli exits with the code matching it.
+func failure(err error, msg string) error {
+	return cli.Exit(fmt.Errorf("%s: %w", msg, err), exitCode(err))
+}
+
+// exitCode maps the typed errors of the contract module to their exit code, `exitFailure` otherwise.
+func exitCode(err error) int {
+	switch {
+	// a zero address is a valid address, check it before the broader invalid address error
+	case errors.Is(err, contract.ErrZeroAddress):
+		return exitZeroAddress
+	case errors.Is(err, contract.ErrInvalidAddress):
+		return exitInvalidAddress
+	case errors.Is(err, contract.ErrNegativeAmount):
+		return exitNegativeAmount
+	case errors.Is(err, contract.ErrInvalidAmount):
+		return exitInvalidAmount
+	case errors.Is(err, contract.ErrInsufficientBalance):
+		return exitInsufficientBalance
+	case errors.Is(err, contract.ErrInsufficientAllowance):
+		return exitInsufficientAllowance
+	case errors.Is(err, contract.ErrChainMismatch):
+		return exitChainMismatch
+	case errors.Is(err, contract.ErrInvalidArgument), errors.Is(err, contract.ErrUnknownMethod), errors.Is(err, contract.ErrNoABI),
+		errors.Is(err, manifest.ErrInvalid), errors.Is(err, contract.ErrInvalidTransaction), errors.Is(err, contract.ErrInvalidDistribution):
+		return exitUsage
+	default:
+		return exitFailure
+	}
+}
+
+// usageError reports invalid command input so the cli exits with `exitUsage`.
+func usageError(format string, a ...interface{}) error {
+	return cli.Exit(fmt.Errorf(format, a...), exitUsage)
+}
+
+// formatAddress prints the address in the format selected with `--address-format`.
+func formatAddress(cCtx *cli.Context, addr common.Address) string {
+	switch cCtx.String("address-format") {
+	case formatBech32:
+		return address.ToBech32(addr)
+	case formatBoth:
+		return fmt.Sprintf("%s (%s)", addr.Hex(), address.ToBech32(addr))
+	default:
+		return addr.Hex()
+	}
+}
+
+// waitFlags are shared by commands sending a transaction, to optionally block until it is mined.
+func waitFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "wait until the transaction is mined and fail if it reverted",
+		},
+		&cli.Uint64Flag{
+			Name:  "confirmations",
+			Usage: "number of `BLOCKS` to wait for, including the one the transaction is mined in",
+			Value: 1,
+		},
+		&cli.DurationFlag{
+			Name:  "wait-timeout",
+			Usage: "maximum `DURATION` to wait for the transaction",
+			Value: contract.DefaultWaitTimeout,
+		},
+	}
+}
+
+// waitMined waits for the transaction when `--wait` is set, it returns a nil reciept otherwise.
+func waitMined(cCtx *cli.Context, c *contract.Contract, txHash common.Hash) (*types.Receipt, error) {
+	if !cCtx.Bool("wait") {
+		return nil, nil
+	}
+
+	log.Info().Msgf("Waiting for %s with %d confirmation(s)", txHash.Hex(), cCtx.Uint64("confirmations"))
+
+	reciept, err := c.WaitMinedContext(cCtx.Context, txHash, contract.WaitOpts{
+		Confirmations: cCtx.Uint64("confirmations"),
+		Timeout:       cCtx.Duration("wait-timeout"),
+	})
+	if err != nil {
+		return nil, failure(err, "transaction not confirmed")
+	}
+
+	log.Info().Msgf("Mined in block %d, gas used %d", reciept.BlockNumber, reciept.GasUsed)
+
+	return reciept, nil
+}
+
+// rawFlag switches token amounts read and printed by a command to base units.
+func rawFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "raw",
+		Usage: "read and print token amounts in base units instead of using the token decimals",
+	}
+}
+
+// tokenAmount parses `--amount`, in base units with `--raw` and converted with the token decimals otherwise.
+func tokenAmount(cCtx *cli.Context, c *contract.Contract, instance contract.IGoldcoin) (*big.Int, error) {
+	if cCtx.Bool("raw") {
+		amount, err := contract.ParseAmount(cCtx.String("amount"))
+		if err != nil {
+			return nil, failure(err, "invalid amount")
+		}
+
+		return amount, nil
+	}
+
+	units, err := c.TokenUnitsContext(cCtx.Context, instance)
+	if err != nil {
+		return nil, failure(err, "unable to read token decimals")
+	}
+
+	amount, err := units.Parse(cCtx.String("amount"))
+	if err != nil {
+		return nil, failure(err, "invalid amount")
+	}
+
+	return amount, nil
+}
+
+// formatAmount prints an amount in base units with `--raw` and as a decimal amount of the token otherwise.
+func formatAmount(cCtx *cli.Context, c *contract.Contract, instance contract.IGoldcoin, amount *big.Int) (string, error) {
+	if cCtx.Bool("raw") {
+		return amount.String(), nil
+	}
+
+	units, err := c.TokenUnitsContext(cCtx.Context, instance)
+	if err != nil {
+		return "", failure(err, "unable to read token decimals")
+	}
+
+	return units.Format(amount), nil
+}
+
+func deployCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:      "deploy",
+		Aliases:   []string{"d"},
+		Usage:     "Deploy the goldcoin smart contract, or any compiled contract, and record it in the registry",
+		ArgsUsage: "[CONSTRUCTOR ARGUMENTS...]",
+		Description: "Without --abi the bundled goldcoin contract is deployed. With --abi and --bin the compiled contract " +
+			"is deployed, the arguments are passed to its constructor in order: numbers in base 10 or 0x hex, addresses " +
+			"in 0x hex or evmos1 bech32, bytes in 0x hex, arrays as JSON, eg. '[\"1\",\"2\"]', and tuples as JSON objects. " +
+			"With --salt the contract is deployed with CREATE2 through the deterministic deployer, so it lands at the same " +
+			"address on every chain, see precompute. The deployer is only set up when missing with --ensure-deployer.",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "abi",
+				Usage: "`FILE` with the JSON ABI of the contract, eg. abi/Goldcoin.abi",
+			},
+			&cli.StringFlag{
+				Name:  "bin",
+				Usage: "`FILE` with the hex creation bytecode of the contract, eg. bin/Goldcoin.bin",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "`NAME` the deployment is recorded under, defaults to the ABI file name without extension",
+			},
+			saltFlag(false),
+			ensureDeployerFlag(),
+		}, waitFlags()...),
+		// constructor arguments are only taken along with an artifact
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.IsSet("abi") {
+				return nil
+			}
+
+			return rejectArgs(cCtx)
+		},
+		Action: func(cCtx *cli.Context) error {
+			name := contract.GoldcoinName
+
+			var address common.Address
+			var txHash common.Hash
+
+			artifact, err := loadArtifact(cCtx)
+			if err != nil {
+				return err
+			}
+
+			if cCtx.Bool("ensure-deployer") && !cCtx.IsSet("salt") {
+				return usageError("--ensure-deployer is only used with --salt")
+			}
+
+			switch {
+			case cCtx.IsSet("salt"):
+				salt, err := contract.ParseSalt(cCtx.String("salt"))
+				if err != nil {
+					return failure(err, "invalid salt")
+				}
+
+				if err := ensureDeployer(cCtx, c); err != nil {
+					return err
+				}
+
+				if artifact == nil {
+					artifact = contract.GoldcoinArtifact()
+				}
+
+				name = artifact.Name
+
+				addr, tx, err := c.DeployCreate2Context(cCtx.Context, artifact, salt, cCtx.Args().Slice()...)
+				if errors.Is(err, contract.ErrAlreadyDeployed) {
+					log.Info().Msgf("Address: %s", formatAddress(cCtx, addr))
+				}
+
+				if err != nil {
+					return failure(err, "unable to deploy")
+				}
+
+				address, txHash = addr, tx.Hash()
+			case artifact != nil:
+				name = artifact.Name
+
+				addr, tx, err := c.DeployArtifactContext(cCtx.Context, artifact, cCtx.Args().Slice()...)
+				if err != nil {
+					return failure(err, "unable to deploy")
+				}
+
+				address, txHash = addr, tx.Hash()
+			default:
+				_, addrHash, hash, err := c.DeployContext(cCtx.Context)
+				if err != nil {
+					return failure(err, "unable to deploy")
+				}
+
+				address, txHash = common.HexToAddress(addrHash), common.HexToHash(hash)
+			}
+
+			log.Info().Msgf("Address: %s", formatAddress(cCtx, address))
+			log.Info().Msgf("TXHash: %s", txHash.Hex())
+
+			reciept, err := waitMined(cCtx, c, txHash)
+			if err != nil {
+				return err
+			}
+
+			if reciept != nil {
+				if err := c.RecordMined(cCtx.Context, name, reciept); err != nil {
+					log.Err(err).Msg("unable to update deployment block number in registry")
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// loadArtifact loads the contract given with `--abi` and `--bin`, nil when neither is set.
+func loadArtifact(cCtx *cli.Context) (*contract.Artifact, error) {
+	if !cCtx.IsSet("abi") && !cCtx.IsSet("bin") {
+		return nil, nil
+	}
+
+	if !cCtx.IsSet("abi") || !cCtx.IsSet("bin") {
+		return nil, usageError("--abi and --bin must be given together")
+	}
+
+	artifact, err := contract.LoadArtifact(cCtx.String("abi"), cCtx.String("bin"))
+	if err != nil {
+		return nil, failure(err, "unable to load contract artifact")
+	}
+
+	if n := cCtx.String("name"); n != "" {
+		artifact.Name = n
+	}
+
+	return artifact, nil
+}
+
+// ensureDeployerFlag opts in to setting up the deterministic deployer, which funds a keyless account.
+func ensureDeployerFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name: "ensure-deployer",
+		Usage: "deploy the deterministic deployer when the chain does not have it, funding the keyless sender of its " +
+			"presigned transaction with 0.01 of the native token first. The funds are lost when the node rejects " +
+			"transactions without chain id, as Evmos does unless its evm params allow unprotected transactions",
+	}
+}
+
+// ensureDeployer sets up the deterministic deployer when `--ensure-deployer` is given, otherwise deployments
+// through it fail with `contract.ErrNoDeployer` on chains without it.
+func ensureDeployer(cCtx *cli.Context, c *contract.Contract) error {
+	if !cCtx.Bool("ensure-deployer") {
+		return nil
+	}
+
+	if _, err := c.EnsureDeployerContext(cCtx.Context); err != nil {
+		return failure(err, "unable to deploy the deterministic deployer")
+	}
+
+	return nil
+}
+
+// saltFlag selects a CREATE2 deployment through the deterministic deployer.
+func saltFlag(required bool) cli.Flag {
+	return &cli.StringFlag{
+		Name:     "salt",
+		Usage:    "CREATE2 `SALT`, 0x hex of up to 32 bytes or any text, which is hashed",
+		Required: required,
+	}
+}
+
+func precomputeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "precompute",
+		Usage:     "Print the address a deploy with --salt deploys to, without a node",
+		ArgsUsage: "[CONSTRUCTOR ARGUMENTS...]",
+		Description: "The address only depends on the salt, the creation bytecode and the constructor arguments, so it " +
+			"is the same on every chain. Without --abi the bundled goldcoin contract is used, the arguments are taken " +
+			"like with deploy.",
+		Flags: []cli.Flag{
+			saltFlag(true),
+			&cli.StringFlag{
+				Name:  "abi",
+				Usage: "`FILE` with the JSON ABI of the contract, eg. abi/Goldcoin.abi",
+			},
+			&cli.StringFlag{
+				Name:  "bin",
+				Usage: "`FILE` with the hex creation bytecode of the contract, eg. bin/Goldcoin.bin",
+			},
+		},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.IsSet("abi") {
+				return nil
+			}
+
+			return rejectArgs(cCtx)
+		},
+		Action: func(cCtx *cli.Context) error {
+			salt, err := contract.ParseSalt(cCtx.String("salt"))
+			if err != nil {
+				return failure(err, "invalid salt")
+			}
+
+			artifact, err := loadArtifact(cCtx)
+			if err != nil {
+				return err
+			}
+
+			if artifact == nil {
+				artifact = contract.GoldcoinArtifact()
+			}
+
+			address, err := artifact.Create2Address(salt, cCtx.Args().Slice()...)
+			if err != nil {
+				return failure(err, "invalid constructor arguments")
+			}
+
+			fmt.Fprintln(cCtx.App.Writer, formatAddress(cCtx, address))
+
+			return nil
+		},
+	}
+}
+
+func addressCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "address",
+		Usage:     "Convert addresses between 0x hex and evmos1 bech32, without a node",
+		ArgsUsage: "ADDRESS...",
+		Flags:     []cli.Flag{outputFormats{outputTable, outputJSON}.flag()},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() == 0 {
+				return usageError("expected at least one address (see --help)")
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputFormats{outputTable, outputJSON}.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			type converted struct {
+				Hex    string `json:"hex"`
+				Bech32 string `json:"bech32"`
+			}
+
+			addrs := make([]converted, 0, cCtx.NArg())
+			for _, arg := range cCtx.Args().Slice() {
+				addr, err := contract.ParseAddress(arg)
+				if err != nil {
+					return failure(err, "invalid address")
+				}
+
+				addrs = append(addrs, converted{Hex: addr.Hex(), Bech32: address.ToBech32(addr)})
+			}
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(addrs)
+			}
+
+			w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "hex\tbech32")
+
+			for _, a := range addrs {
+				fmt.Fprintf(w, "%s\t%s\n", a.Hex, a.Bech32)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+func receiptCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:    "receipt",
+		Aliases: []string{"reciept", "r"},
+		Usage:   "Check if the latest deployed smart contract is mined",
+		Action: func(cCtx *cli.Context) error {
+			reciept, err := c.RecieptContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to get reciept")
+			}
+
+			log.Info().Msgf("Reciept: %v", reciept)
+
+			return nil
+		},
+	}
+}
+
+func deploymentsCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:  "deployments",
+		Usage: "List contracts deployed on the connected chain",
+		Action: func(cCtx *cli.Context) error {
+			records, err := c.DeploymentsContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to list deployments")
+			}
+
+			for _, rec := range records {
+				log.Info().Msgf("%s %s tx=%s block=%d at=%s", rec.Name, formatAddress(cCtx, rec.Address), rec.TxHash.Hex(), rec.BlockNumber, rec.Timestamp)
+			}
+
+			return nil
+		},
+	}
+}
+
+func transferCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:    "transfer",
+		Aliases: []string{"t"},
+		Usage:   "Transfer tokens from the owner address to the recipient",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "recipient `ADDRESS` (0x hex or evmos1 bech32)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "amount",
+				Usage:    "`AMOUNT` of tokens, eg. 12.5 or \"12.5 GLD\", in base units with --raw",
+				Required: true,
+			},
+			rawFlag(),
+		}, waitFlags()...),
+		Action: func(cCtx *cli.Context) error {
+			to, err := contract.ParseAddress(cCtx.String("to"))
+			if err != nil {
+				return failure(err, "invalid recipient")
+			}
+
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			amount, err := tokenAmount(cCtx, c, instance)
+			if err != nil {
+				return err
+			}
+
+			tx, err := c.TransferTokensContext(cCtx.Context, instance, to.Hex(), amount.String())
+			if err != nil {
+				return failure(err, "transaction failed")
+			}
+
+			log.Info().Msgf("To: %s", formatAddress(cCtx, to))
+			log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+			_, err = waitMined(cCtx, c, tx.Hash())
+
+			return err
+		},
+	}
+}
+
+func balanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:    "balance",
+		Aliases: []string{"balanceOf", "b"},
+		Usage:   "Check token balance of an address, defaults to the owner address",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "address",
+				Usage: "`ADDRESS` (0x hex or evmos1 bech32) to check, the owner address is used when empty",
+			},
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			account := cCtx.String("address")
+			if account != "" {
+				addr, err := contract.ParseAddress(account)
+				if err != nil {
+					return failure(err, "invalid address")
+				}
+
+				log.Info().Msgf("Address: %s", formatAddress(cCtx, addr))
+			}
+
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			bal, err := c.CheckBalContext(cCtx.Context, instance, account)
+			if err != nil {
+				return failure(err, "unable to get balance")
+			}
+
+			formatted, err := formatAmount(cCtx, c, instance, bal)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("Balance: %s", formatted)
+
+			return nil
+		},
+	}
+}
+
+// spenderFlags are shared by commands changing the allowance of a spender.
+func spenderFlags() []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "spender",
+			Usage:    "`ADDRESS` (0x hex or evmos1 bech32) allowed to spend the tokens",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "amount",
+			Usage:    "`AMOUNT` of tokens, eg. 12.5 or \"12.5 GLD\", in base units with --raw",
+			Required: true,
+		},
+		rawFlag(),
+	}, waitFlags()...)
+}
+
+// allowanceAction returns the action of a command sending an allowance transaction built by send.
+func allowanceAction(c *contract.Contract, send func(context.Context, contract.IGoldcoin, string, string) (*types.Transaction, error)) cli.ActionFunc {
+	return func(cCtx *cli.Context) error {
+		spender, err := contract.ParseAddress(cCtx.String("spender"))
+		if err != nil {
+			return failure(err, "invalid spender")
+		}
+
+		instance, err := c.LoadContext(cCtx.Context)
+		if err != nil {
+			return failure(err, "unable to load contract")
+		}
+
+		amount, err := tokenAmount(cCtx, c, instance)
+		if err != nil {
+			return err
+		}
+
+		tx, err := send(cCtx.Context, instance, spender.Hex(), amount.String())
+		if err != nil {
+			return failure(err, "transaction failed")
+		}
+
+		log.Info().Msgf("Spender: %s", formatAddress(cCtx, spender))
+		log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+		_, err = waitMined(cCtx, c, tx.Hash())
+
+		return err
+	}
+}
+
+func approveCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "approve",
+		Usage:  "Allow the spender to transfer up to the amount of tokens from the owner address",
+		Flags:  spenderFlags(),
+		Action: allowanceAction(c, c.ApproveContext),
+	}
+}
+
+func increaseAllowanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "increase-allowance",
+		Usage:  "Add the amount to the tokens the spender may transfer from the owner address",
+		Flags:  spenderFlags(),
+		Action: allowanceAction(c, c.IncreaseAllowanceContext),
+	}
+}
+
+func decreaseAllowanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "decrease-allowance",
+		Usage:  "Remove the amount from the tokens the spender may transfer from the owner address",
+		Flags:  spenderFlags(),
+		Action: allowanceAction(c, c.DecreaseAllowanceContext),
+	}
+}
+
+func allowanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:  "allowance",
+		Usage: "Check the amount of tokens the spender may still transfer from the owner",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "owner",
+				Usage: "`ADDRESS` (0x hex or evmos1 bech32) holding the tokens, the owner address is used when empty",
+			},
+			&cli.StringFlag{
+				Name:     "spender",
+				Usage:    "`ADDRESS` (0x hex or evmos1 bech32) allowed to spend the tokens",
+				Required: true,
+			},
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			allowance, err := c.AllowanceContext(cCtx.Context, instance, cCtx.String("owner"), cCtx.String("spender"))
+			if err != nil {
+				return failure(err, "unable to get allowance")
+			}
+
+			formatted, err := formatAmount(cCtx, c, instance, allowance)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("Allowance: %s", formatted)
+
+			return nil
+		},
+	}
+}
+
+func transferFromCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:  "transfer-from",
+		Usage: "Transfer tokens from a holder to the recipient out of the allowance given to the owner address",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "holder `ADDRESS` (0x hex or evmos1 bech32) the tokens are taken from",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "recipient `ADDRESS` (0x hex or evmos1 bech32)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "amount",
+				Usage:    "`AMOUNT` of tokens, eg. 12.5 or \"12.5 GLD\", in base units with --raw",
+				Required: true,
+			},
+			rawFlag(),
+		}, waitFlags()...),
+		Action: func(cCtx *cli.Context) error {
+			from, err := contract.ParseAddress(cCtx.String("from"))
+			if err != nil {
+				return failure(err, "invalid holder")
+			}
+
+			to, err := contract.ParseAddress(cCtx.String("to"))
+			if err != nil {
+				return failure(err, "invalid recipient")
+			}
+
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			amount, err := tokenAmount(cCtx, c, instance)
+			if err != nil {
+				return err
+			}
+
+			tx, err := c.TransferFromContext(cCtx.Context, instance, from.Hex(), to.Hex(), amount.String())
+			if err != nil {
+				return failure(err, "transaction failed")
+			}
+
+			log.Info().Msgf("From: %s", formatAddress(cCtx, from))
+			log.Info().Msgf("To: %s", formatAddress(cCtx, to))
+			log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+			_, err = waitMined(cCtx, c, tx.Hash())
+
+			return err
+		},
+	}
+}
+
+func distributeCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON, outputCSV}
+
+	return &cli.Command{
+		Name:      "distribute",
+		Usage:     "Transfer tokens from the owner address to every recipient of a CSV file, resuming where a previous run stopped",
+		ArgsUsage: "CSV",
+		Description: "The file lists one address,amount row per recipient, an address,amount header and lines starting with # " +
+			"are skipped. Every row and the total against the owner balance are checked before anything is sent. Transfers " +
+			"are sent with consecutive nonces, --concurrency at a time, and waited for. The progress is kept in the state " +
+			"file: rows already paid are skipped and transfers sent by an interrupted run are waited for instead of being " +
+			"sent again. A report of the rows, their transaction and status is printed at the end.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "state",
+				Usage: "`FILE` keeping the progress of the distribution, defaults to the CSV file name followed by .state.json",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "maximum `NUMBER` of transfers in flight at once",
+				Value: 4,
+			},
+			&cli.Uint64Flag{
+				Name:  "confirmations",
+				Usage: "number of `BLOCKS`, including the one it is mined in, every transfer is waited for",
+				Value: 1,
+			},
+			&cli.DurationFlag{
+				Name:  "wait-timeout",
+				Usage: "give up waiting for a transfer after `DURATION`",
+				Value: contract.DefaultWaitTimeout,
+			},
+			rawFlag(),
+			outputs.flag(),
+		},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return usageError("expected a CSV file, got %v (see --help)", cCtx.Args().Slice())
+			}
+
+			if cCtx.Int("concurrency") < 1 {
+				return usageError("--concurrency must be at least 1")
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			var units *contract.TokenUnits
+			if !cCtx.Bool("raw") {
+				u, err := c.TokenUnitsContext(cCtx.Context, instance)
+				if err != nil {
+					return failure(err, "unable to read token decimals")
+				}
+
+				units = &u
+			}
+
+			path := cCtx.Args().First()
+
+			f, err := os.Open(path)
+			if err != nil {
+				return failure(err, "unable to read distribution")
+			}
+			defer f.Close()
+
+			payments, err := contract.ParsePayments(f, units)
+			if err != nil {
+				return failure(err, "invalid distribution")
+			}
+
+			statePath := cCtx.String("state")
+			if statePath == "" {
+				statePath = path + ".state.json"
+			}
+
+			state, err := contract.LoadDistributionState(statePath)
+			if err != nil {
+				return failure(err, "unable to read distribution state")
+			}
+
+			// the rows handled before a failure are printed as well, the next run skips the paid ones
+			results, distErr := c.DistributeContext(cCtx.Context, instance, payments, state, contract.DistributeOpts{
+				Concurrency: cCtx.Int("concurrency"),
+				Wait:        contract.WaitOpts{Confirmations: cCtx.Uint64("confirmations"), Timeout: cCtx.Duration("wait-timeout")},
+			})
+
+			if err := writeReport(cCtx, output, units, results); err != nil {
+				return err
+			}
+
+			if distErr != nil {
+				return failure(distErr, "distribution incomplete")
+			}
+
+			return nil
+		},
+	}
+}
+
+// writeReport prints the results of a distribution, amounts are printed in base units when units is nil.
+func writeReport(cCtx *cli.Context, output string, units *contract.TokenUnits, results []contract.PaymentResult) error {
+	if output == outputJSON {
+		if results == nil {
+			results = []contract.PaymentResult{}
+		}
+
+		return json.NewEncoder(cCtx.App.Writer).Encode(results)
+	}
+
+	header := []string{"row", "to", "amount", "status", "tx", "error"}
+	records := make([][]string, 0, len(results))
+
+	for _, r := range results {
+		amount := r.Amount.String()
+		if units != nil {
+			amount = units.Format(r.Amount)
+		}
+
+		tx := ""
+		if r.TxHash != (common.Hash{}) {
+			tx = r.TxHash.Hex()
+		}
+
+		records = append(records, []string{strconv.Itoa(r.Row), formatAddress(cCtx, r.To), amount, r.Status, tx, r.Error})
+	}
+
+	if output == outputCSV {
+		return csv.NewWriter(cCtx.App.Writer).WriteAll(append([][]string{header}, records...))
+	}
+
+	w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+	for _, r := range append([][]string{header}, records...) {
+		fmt.Fprintln(w, strings.Join(r, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// outputFormats lists the formats a command can print its result as, the first one is the default.
+type outputFormats []string
+
+// flag returns the `--output` flag selecting one of the formats.
+func (f outputFormats) flag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "print the result as `FORMAT`, one of " + strings.Join(f, ", "),
+		Value:   f[0],
+	}
+}
+
+// get returns the format selected with `--output`, failing with a usage error for unknown ones.
+func (f outputFormats) get(cCtx *cli.Context) (string, error) {
+	format := cCtx.String("output")
+	for _, allowed := range f {
+		if format == allowed {
+			return format, nil
+		}
+	}
+
+	return "", usageError("invalid output format %q, expected one of %s", format, strings.Join(f, ", "))
+}
+
+func infoCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON}
+
+	return &cli.Command{
+		Name:  "info",
+		Usage: "Show the metadata of the latest deployed token",
+		Flags: []cli.Flag{
+			outputs.flag(),
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			instance, err := c.LoadContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to load contract")
+			}
+
+			info, err := c.TokenInfoContext(cCtx.Context, instance)
+			if err != nil {
+				return failure(err, "unable to get token info")
+			}
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(info)
+			}
+
+			supply := info.Units().Format(info.TotalSupply)
+			if cCtx.Bool("raw") {
+				supply = info.TotalSupply.String()
+			}
+
+			deployBlock := "pending"
+			if info.DeployBlock != 0 {
+				deployBlock = strconv.FormatUint(info.DeployBlock, 10)
+			}
+
+			w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Name\t%s\n", info.Name)
+			fmt.Fprintf(w, "Symbol\t%s\n", info.Symbol)
+			fmt.Fprintf(w, "Decimals\t%d\n", info.Decimals)
+			fmt.Fprintf(w, "Total supply\t%s\n", supply)
+			fmt.Fprintf(w, "Chain ID\t%d\n", info.ChainID)
+			fmt.Fprintf(w, "Address\t%s\n", formatAddress(cCtx, info.Address))
+			fmt.Fprintf(w, "Code hash\t%s\n", info.CodeHash.Hex())
+			fmt.Fprintf(w, "Deploy tx\t%s\n", info.DeployTx.Hex())
+			fmt.Fprintf(w, "Deploy block\t%s\n", deployBlock)
+
+			return w.Flush()
+		},
+	}
+}
+
+// eventWriter streams token events in one of the `history` output formats.
+type eventWriter struct {
+	cCtx   *cli.Context
+	output string
+	units  *contract.TokenUnits
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+// newEventWriter writes the header of the output, amounts are printed in base units when units is nil.
+func newEventWriter(cCtx *cli.Context, output string, units *contract.TokenUnits) *eventWriter {
+	w := &eventWriter{cCtx: cCtx, output: output, units: units}
+	header := []string{"block", "log", "event", "from", "to", "value", "tx"}
+
+	switch output {
+	case outputCSV:
+		w.csv = csv.NewWriter(cCtx.App.Writer)
+		_ = w.csv.Write(header)
+	case outputJSONL:
+		w.json = json.NewEncoder(cCtx.App.Writer)
+	default:
+		w.row(header)
+	}
+
+	return w
+}
+
+// write prints a single event, csv output is flushed right away so events stream as they are fetched.
+func (w *eventWriter) write(ev contract.Event) error {
+	if w.json != nil {
+		return w.json.Encode(ev)
+	}
+
+	value := ev.Value.String()
+	if w.units != nil {
+		value = w.units.Format(ev.Value)
+	}
+
+	record := []string{
+		strconv.FormatUint(ev.BlockNumber, 10),
+		strconv.FormatUint(uint64(ev.LogIndex), 10),
+		ev.Name,
+		formatAddress(w.cCtx, ev.From),
+		formatAddress(w.cCtx, ev.To),
+		value,
+		ev.TxHash.Hex(),
+	}
+
+	if w.csv != nil {
+		if err := w.csv.Write(record); err != nil {
+			return err
+		}
+
+		w.csv.Flush()
+
+		return w.csv.Error()
+	}
+
+	w.row(record)
+
+	return nil
+}
+
+// row prints a table row with fixed column widths, tabwriter would hold rows back until the end of the output.
+func (w *eventWriter) row(r []string) {
+	fmt.Fprintf(w.cCtx.App.Writer, "%-10s %-4s %-9s %-42s %-42s %-28s %s\n", r[0], r[1], r[2], r[3], r[4], r[5], r[6])
+}
+
+// eventNames reads the repeated `--event` flag, names are matched case insensitively.
+func eventNames(cCtx *cli.Context) ([]string, error) {
+	var events []string
+	for _, name := range cCtx.StringSlice("event") {
+		switch {
+		case strings.EqualFold(name, contract.EventTransfer):
+			events = append(events, contract.EventTransfer)
+		case strings.EqualFold(name, contract.EventApproval):
+			events = append(events, contract.EventApproval)
+		default:
+			return nil, usageError("invalid event %q, expected Transfer or Approval", name)
+		}
+	}
+
+	return events, nil
+}
+
+// eventUnits returns the token units amounts are printed with, nil with `--raw` and for jsonl output.
+func eventUnits(cCtx *cli.Context, c *contract.Contract, output string) (*contract.TokenUnits, error) {
+	if cCtx.Bool("raw") || output == outputJSONL {
+		return nil, nil
+	}
+
+	instance, err := c.LoadContext(cCtx.Context)
+	if err != nil {
+		return nil, failure(err, "unable to load contract")
+	}
+
+	units, err := c.TokenUnitsContext(cCtx.Context, instance)
+	if err != nil {
+		return nil, failure(err, "unable to read token decimals")
+	}
+
+	return &units, nil
+}
+
+func historyCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSONL, outputCSV}
+
+	return &cli.Command{
+		Name:  "history",
+		Usage: "List Transfer and Approval events of the deployed token",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "address",
+				Usage: "only list events of `ADDRESS` (0x hex or evmos1 bech32), all events when empty",
+			},
+			&cli.StringFlag{
+				Name:  "direction",
+				Usage: "side of the events the address is on, `DIRECTION` is one of any, from (sender or owner) or to (reciever or spender)",
+				Value: string(contract.DirectionAny),
+			},
+			&cli.StringSliceFlag{
+				Name:  "event",
+				Usage: "only list `EVENT`s of the given kind, Transfer or Approval, may be repeated",
+			},
+			&cli.Uint64Flag{
+				Name:  "from-block",
+				Usage: "first `BLOCK` to list events of, defaults to the deploy block",
+			},
+			&cli.Uint64Flag{
+				Name:  "to-block",
+				Usage: "last `BLOCK` to list events of, defaults to the latest block",
+			},
+			&cli.Uint64Flag{
+				Name:  "chunk-size",
+				Usage: "number of `BLOCKS` queried from the node at once",
+				Value: contract.DefaultChunkSize,
+			},
+			outputs.flag(),
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			query := contract.HistoryQuery{
+				Direction: contract.Direction(cCtx.String("direction")),
+				FromBlock: cCtx.Uint64("from-block"),
+				ChunkSize: cCtx.Uint64("chunk-size"),
+			}
+
+			switch query.Direction {
+			case contract.DirectionAny, contract.DirectionFrom, contract.DirectionTo:
+			default:
+				return usageError("invalid direction %q, expected any, from or to", query.Direction)
+			}
+
+			if query.Events, err = eventNames(cCtx); err != nil {
+				return err
+			}
+
+			if cCtx.IsSet("to-block") {
+				toBlock := cCtx.Uint64("to-block")
+				query.ToBlock = &toBlock
+			}
+
+			if account := cCtx.String("address"); account != "" {
+				if query.Account, err = contract.ParseAddress(account); err != nil {
+					return failure(err, "invalid address")
+				}
+			}
+
+			units, err := eventUnits(cCtx, c, output)
+			if err != nil {
+				return err
+			}
+
+			w := newEventWriter(cCtx, output, units)
+			if err := c.HistoryContext(cCtx.Context, query, w.write); err != nil {
+				return failure(err, "unable to list events")
+			}
+
+			return nil
+		},
+	}
+}
+
+// parseAddresses parses every value of a repeated address flag.
+func parseAddresses(cCtx *cli.Context, name string) ([]common.Address, error) {
+	var addrs []common.Address
+	for _, s := range cCtx.StringSlice(name) {
+		addr, err := contract.ParseAddress(s)
+		if err != nil {
+			return nil, failure(err, "invalid "+name+" address")
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+// minValue parses `--min-amount` in base units with `--raw` and converted with the token decimals otherwise,
+// jsonl output prints base units but the minimum is still given in tokens.
+func minValue(cCtx *cli.Context, c *contract.Contract, units *contract.TokenUnits, s string) (*big.Int, error) {
+	if cCtx.Bool("raw") {
+		amount, err := contract.ParseAmount(s)
+		if err != nil {
+			return nil, failure(err, "invalid minimum amount")
+		}
+
+		return amount, nil
+	}
+
+	if units == nil {
+		var err error
+		if units, err = eventUnits(cCtx, c, outputTable); err != nil {
+			return nil, err
+		}
+	}
+
+	amount, err := units.Parse(s)
+	if err != nil {
+		return nil, failure(err, "invalid minimum amount")
+	}
+
+	return amount, nil
+}
+
+func watchCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSONL, outputCSV}
+
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "Stream new Transfer and Approval events of the deployed token until interrupted",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "from",
+				Usage: "only stream events sent or approved by `ADDRESS` (0x hex or evmos1 bech32), may be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "to",
+				Usage: "only stream events recieved by or approved to `ADDRESS` (0x hex or evmos1 bech32), may be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "min-amount",
+				Usage: "only stream events with a value of at least `AMOUNT`",
+			},
+			&cli.StringSliceFlag{
+				Name:  "event",
+				Usage: "only stream `EVENT`s of the given kind, Transfer or Approval, may be repeated",
+			},
+			&cli.Uint64Flag{
+				Name:  "from-block",
+				Usage: "first `BLOCK` to stream events of, defaults to the block after the latest one",
+			},
+			&cli.DurationFlag{
+				Name:  "poll-interval",
+				Usage: "how often logs are polled for when the node does not support subscriptions",
+				Value: contract.DefaultPollInterval,
+			},
+			outputs.flag(),
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			query := contract.WatchQuery{PollInterval: cCtx.Duration("poll-interval")}
+			if query.Events, err = eventNames(cCtx); err != nil {
+				return err
+			}
+
+			if query.From, err = parseAddresses(cCtx, "from"); err != nil {
+				return err
+			}
+
+			if query.To, err = parseAddresses(cCtx, "to"); err != nil {
+				return err
+			}
+
+			if cCtx.IsSet("from-block") {
+				fromBlock := cCtx.Uint64("from-block")
+				query.FromBlock = &fromBlock
+			}
+
+			units, err := eventUnits(cCtx, c, output)
+			if err != nil {
+				return err
+			}
+
+			if minAmount := cCtx.String("min-amount"); minAmount != "" {
+				if query.MinValue, err = minValue(cCtx, c, units, minAmount); err != nil {
+					return err
+				}
+			}
+
+			w := newEventWriter(cCtx, output, units)
+			err = c.WatchContext(cCtx.Context, query, w.write)
+			if errors.Is(err, context.Canceled) {
+				// interrupted by the user
+				return nil
+			}
+
+			if err != nil {
+				return failure(err, "unable to watch events")
+			}
+
+			return nil
+		},
+	}
+}
+
+func indexCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:  "index",
+		Usage: "Maintain a local index of token events and serve balances and allowances from it",
+		Subcommands: []*cli.Command{
+			indexSyncCommand(c),
+			indexBalanceCommand(c),
+			indexAllowanceCommand(c),
+			indexHoldersCommand(c),
+		},
+	}
+}
+
+func indexSyncCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "sync",
+		Usage:  "Index new Transfer and Approval events, rolling back events orphaned by a reorg",
+		Before: rejectArgs,
+		Flags: []cli.Flag{
+			&cli.Uint64Flag{
+				Name:  "chunk-size",
+				Usage: "number of `BLOCKS` queried from the node at once",
+				Value: contract.DefaultChunkSize,
+			},
+			&cli.Uint64Flag{
+				Name:  "confirmations",
+				Usage: "number of latest `BLOCKS` kept out of the index",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			result, err := c.SyncIndexContext(cCtx.Context, contract.IndexQuery{
+				ChunkSize:     cCtx.Uint64("chunk-size"),
+				Confirmations: cCtx.Uint64("confirmations"),
+			})
+			if err != nil {
+				return failure(err, "unable to sync index")
+			}
+
+			if result.Reorg != nil {
+				log.Warn().Msgf("Reorg: %d events after block %d rolled back", result.Reorg.Removed, result.Reorg.Ancestor)
+			}
+
+			if result.FromBlock > result.ToBlock {
+				log.Info().Msgf("Index up to date at block %d", result.ToBlock)
+				return nil
+			}
+
+			log.Info().Msgf("Indexed %d events of blocks %d-%d", result.Events, result.FromBlock, result.ToBlock)
+
+			return nil
+		},
+	}
+}
+
+func indexBalanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "balance",
+		Usage:  "Show the indexed token balance of an address, defaults to the owner address",
+		Before: rejectArgs,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "address",
+				Usage: "`ADDRESS` (0x hex or evmos1 bech32) to check, the owner address is used when empty",
+			},
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			bal, err := c.IndexedBalanceContext(cCtx.Context, cCtx.String("address"))
+			if err != nil {
+				return failure(err, "unable to get indexed balance")
+			}
+
+			formatted, err := indexedAmount(cCtx, c, bal)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("Balance: %s", formatted)
+
+			return nil
+		},
+	}
+}
+
+func indexAllowanceCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:   "allowance",
+		Usage:  "Show the indexed allowance of the spender from the owner",
+		Before: rejectArgs,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "owner",
+				Usage: "`ADDRESS` (0x hex or evmos1 bech32) holding the tokens, the owner address is used when empty",
+			},
+			&cli.StringFlag{
+				Name:     "spender",
+				Usage:    "`ADDRESS` (0x hex or evmos1 bech32) allowed to spend the tokens",
+				Required: true,
+			},
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			allowance, err := c.IndexedAllowanceContext(cCtx.Context, cCtx.String("owner"), cCtx.String("spender"))
+			if err != nil {
+				return failure(err, "unable to get indexed allowance")
+			}
+
+			formatted, err := indexedAmount(cCtx, c, allowance)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("Allowance: %s", formatted)
+
+			return nil
+		},
+	}
+}
+
+func indexHoldersCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON}
+
+	return &cli.Command{
+		Name:   "holders",
+		Usage:  "List every address holding tokens according to the index",
+		Before: rejectArgs,
+		Flags: []cli.Flag{
+			outputs.flag(),
+			rawFlag(),
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			holders, err := c.IndexedHoldersContext(cCtx.Context)
+			if err != nil {
+				return failure(err, "unable to list indexed holders")
+			}
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(holders)
+			}
+
+			units, err := eventUnits(cCtx, c, output)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "address\tbalance")
+
+			for _, h := range holders {
+				balance := h.Balance.String()
+				if units != nil {
+					balance = units.Format(h.Balance)
+				}
+
+				fmt.Fprintf(w, "%s\t%s\n", formatAddress(cCtx, h.Address), balance)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+// indexedAmount formats an amount read from the index, only the token decimals are read from the node and
+// not even those with `--raw`.
+func indexedAmount(cCtx *cli.Context, c *contract.Contract, amount *big.Int) (string, error) {
+	units, err := eventUnits(cCtx, c, outputTable)
+	if err != nil {
+		return "", err
+	}
+
+	if units == nil {
+		return amount.String(), nil
+	}
+
+	return units.Format(amount), nil
+}
+
+// methodArgs checks the `<contract> <method> [arguments...]` positional arguments of `call` and `send`.
+func methodArgs(cCtx *cli.Context) error {
+	if cCtx.NArg() < 2 {
+		return usageError("expected a contract and a method, got %v (see --help)", cCtx.Args().Slice())
+	}
+
+	return nil
+}
+
+// methodFlags are shared by `call` and `send`.
+func methodFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "abi",
+			Usage: "`FILE` with the JSON ABI of the contract, defaults to the ABI recorded in the registry",
+		},
+		outputFormats{outputTable, outputJSON}.flag(),
+	}
+}
+
+// methodDescription documents how `call` and `send` take their arguments.
+const methodDescription = "The contract is a name recorded in the registry or an address in 0x hex or evmos1 bech32. " +
+	"The arguments are passed to the method in order: numbers in base 10 or 0x hex, addresses in 0x hex or evmos1 " +
+	"bech32, bytes in 0x hex, arrays as JSON, eg. '[\"1\",\"2\"]', and tuples as JSON objects keyed by component " +
+	"name or JSON arrays, eg. '{\"to\":\"0x...\",\"amount\":\"1\"}'."
+
+// resolveMethod resolves the contract and method named by the positional arguments.
+func resolveMethod(cCtx *cli.Context, c *contract.Contract) (*contract.Target, string, []string, error) {
+	args := cCtx.Args().Slice()
+
+	target, err := c.ResolveContext(cCtx.Context, args[0], cCtx.String("abi"))
+	if err != nil {
+		return nil, "", nil, failure(err, "unable to resolve contract")
+	}
+
+	return target, args[1], args[2:], nil
+}
+
+// writeValues prints decoded return values or event arguments as aligned name, type and value columns.
+func writeValues(w *tabwriter.Writer, indent string, vals []contract.Value) {
+	for i, v := range vals {
+		name := v.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+
+		fmt.Fprintf(w, "%s%s\t%s\t%s\n", indent, name, v.Type, v)
+	}
+}
+
+func callCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON}
+
+	return &cli.Command{
+		Name:        "call",
+		Usage:       "Call a read-only method of a deployed contract and print its return values",
+		ArgsUsage:   "<CONTRACT> <METHOD> [ARGUMENTS...]",
+		Description: methodDescription,
+		Flags:       methodFlags(),
+		Before:      methodArgs,
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			target, method, args, err := resolveMethod(cCtx, c)
+			if err != nil {
+				return err
+			}
+
+			vals, err := c.CallContext(cCtx.Context, target, method, args...)
+			if err != nil {
+				return failure(err, "call failed")
+			}
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(vals)
+			}
+
+			w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+			writeValues(w, "", vals)
+
+			return w.Flush()
+		},
+	}
+}
+
+func sendCommand(c *contract.Contract) *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON}
+
+	return &cli.Command{
+		Name:        "send",
+		Usage:       "Send a transaction calling a method of a deployed contract",
+		ArgsUsage:   "<CONTRACT> <METHOD> [ARGUMENTS...]",
+		Description: methodDescription + " With --wait the events emitted by the transaction are decoded and printed.",
+		Flags: append(append(methodFlags(),
+			&cli.StringFlag{
+				Name:  "value",
+				Usage: "`WEI` sent along with the transaction, the method must be payable",
+			},
+		), waitFlags()...),
+		Before: methodArgs,
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			var value *big.Int
+			if cCtx.IsSet("value") {
+				if value, err = contract.ParseAmount(cCtx.String("value")); err != nil {
+					return failure(err, "invalid value")
+				}
+			}
+
+			target, method, args, err := resolveMethod(cCtx, c)
+			if err != nil {
+				return err
+			}
+
+			tx, err := c.SendContext(cCtx.Context, target, method, value, args...)
+			if err != nil {
+				return failure(err, "transaction failed")
+			}
+
+			log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+			reciept, err := waitMined(cCtx, c, tx.Hash())
+			if err != nil || reciept == nil {
+				return err
+			}
+
+			events := target.DecodeEvents(reciept.Logs)
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(events)
+			}
+
+			w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+			for _, ev := range events {
+				fmt.Fprintf(w, "%s\t%s\tlog %d\n", ev.Name, formatAddress(cCtx, ev.Address), ev.LogIndex)
+				writeValues(w, "  ", ev.Args)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+func compileCommand() *cli.Command {
+	outputs := outputFormats{outputTable, outputJSON}
+
+	return &cli.Command{
+		Name:      "compile",
+		Usage:     "Compile Solidity sources into ABI, bytecode, metadata and Go bindings",
+		ArgsUsage: "<SOURCE.sol>...",
+		Description: "Every contract declared in the given sources is written to <out>/abi/<Contract>.abi, " +
+			"<out>/bin/<Contract>.bin and <out>/metadata/<Contract>.json along with a Go binding in " +
+			"<out>/<package>/<package>.go, the package is the lowercased contract name unless --pkg is given. Imports " +
+			"are resolved from the base path and the include paths.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "solc",
+				Usage:   "solc `BINARY` to compile with",
+				EnvVars: []string{"SOLC"},
+				Value:   compiler.DefaultSolc,
+			},
+			&cli.StringFlag{
+				Name:  "base-path",
+				Usage: "`DIR` sources and imports are resolved from",
+				Value: ".",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include-path",
+				Usage: "`DIR` searched for imports not found under the base path, may be repeated",
+				Value: cli.NewStringSlice(compiler.DefaultIncludePath),
+			},
+			&cli.BoolFlag{
+				Name:  "optimize",
+				Usage: "enable the optimizer",
+			},
+			&cli.IntFlag{
+				Name:  "optimize-runs",
+				Usage: "number of `RUNS` the optimizer tunes for",
+				Value: compiler.DefaultRuns,
+			},
+			&cli.StringFlag{
+				Name:  "evm-version",
+				Usage: "target EVM `VERSION`, eg. london, the solc default when empty",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "`DIR` the artifacts and bindings are written to",
+				Value: ".",
+			},
+			&cli.StringFlag{
+				Name:  "pkg",
+				Usage: "Go `PACKAGE` of the binding, only when the sources declare a single contract",
+			},
+			&cli.BoolFlag{
+				Name:  "no-binding",
+				Usage: "do not generate Go bindings",
+			},
+			outputs.flag(),
+		},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() == 0 {
+				return usageError("expected at least one source file (see --help)")
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputs.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			solc := &compiler.Solc{
+				Path:         cCtx.String("solc"),
+				BasePath:     cCtx.String("base-path"),
+				IncludePaths: cCtx.StringSlice("include-path"),
+				Optimize:     cCtx.Bool("optimize"),
+				Runs:         cCtx.Int("optimize-runs"),
+				EVMVersion:   cCtx.String("evm-version"),
+			}
+
+			result, err := solc.Compile(cCtx.Context, cCtx.Args().Slice()...)
+
+			var compileErr *compiler.CompileError
+			if errors.As(err, &compileErr) {
+				if output == outputJSON {
+					if err := json.NewEncoder(cCtx.App.Writer).Encode(compileErr.Diagnostics); err != nil {
+						return err
+					}
+				} else {
+					// solc's own formatting quotes the offending source line
+					for _, d := range compileErr.Diagnostics {
+						if d.Formatted == "" {
+							d.Formatted = d.String()
+						}
+
+						fmt.Fprintln(cCtx.App.Writer, d.Formatted)
+					}
+				}
+			}
+
+			if err != nil {
+				return failure(err, "unable to compile")
+			}
+
+			for _, w := range result.Warnings {
+				log.Warn().Msg(w.String())
+			}
+
+			pkg := cCtx.String("pkg")
+			if pkg != "" && len(result.Artifacts) > 1 {
+				return usageError("--pkg given for %d contracts, compile them one at a time", len(result.Artifacts))
+			}
+
+			var written []string
+
+			for _, a := range result.Artifacts {
+				paths, err := a.Write(cCtx.String("out"))
+				if err != nil {
+					return failure(err, "unable to write artifacts of "+a.Name)
+				}
+
+				if !cCtx.Bool("no-binding") {
+					path, err := a.WriteBinding(cCtx.String("out"), pkg)
+					if err != nil {
+						return failure(err, "unable to write binding of "+a.Name)
+					}
+
+					paths = append(paths, path)
+				}
+
+				written = append(written, paths...)
+
+				log.Info().Msgf("Compiled %s from %s", a.Name, a.Source)
+			}
+
+			if output == outputJSON {
+				return json.NewEncoder(cCtx.App.Writer).Encode(written)
+			}
+
+			for _, path := range written {
+				fmt.Fprintln(cCtx.App.Writer, path)
+			}
+
+			return nil
+		},
+	}
+}
+
+func applyCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:      "apply",
+		Usage:     "Deploy the contracts of a manifest and make its calls, resuming where a previous run stopped",
+		ArgsUsage: "MANIFEST",
+		Description: "The manifest is a YAML or JSON file listing contracts, with their abi and bin files and constructor " +
+			"arguments, and calls made once they are deployed. Arguments reference deployed contracts as ${Name}, which " +
+			"also orders the steps. Every transaction is waited for before the next step and the progress is kept in the " +
+			"registry: steps already applied are skipped and a failed run resumes at the step that failed. Contracts " +
+			"with a salt are deployed through the deterministic deployer, which is only set up with --ensure-deployer.",
+		Flags: []cli.Flag{
+			ensureDeployerFlag(),
+			&cli.Uint64Flag{
+				Name:  "confirmations",
+				Usage: "number of `BLOCKS`, including the one it is mined in, every transaction is waited for",
+				Value: 1,
+			},
+			&cli.DurationFlag{
+				Name:  "wait-timeout",
+				Usage: "give up waiting for a transaction after `DURATION`",
+				Value: contract.DefaultWaitTimeout,
+			},
+			outputFormats{outputTable, outputJSON}.flag(),
+		},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return usageError("expected a manifest file, got %v (see --help)", cCtx.Args().Slice())
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			output, err := outputFormats{outputTable, outputJSON}.get(cCtx)
+			if err != nil {
+				return err
+			}
+
+			m, err := manifest.Load(cCtx.Args().First())
+			if err != nil {
+				return failure(err, "unable to load manifest")
+			}
+
+			for _, d := range m.Contracts {
+				if d.Salt != "" {
+					if err := ensureDeployer(cCtx, c); err != nil {
+						return err
+					}
+
+					break
+				}
+			}
+
+			wait := contract.WaitOpts{Confirmations: cCtx.Uint64("confirmations"), Timeout: cCtx.Duration("wait-timeout")}
+
+			// the steps handled before a failure are printed as well, they are skipped by the next run
+			results, applyErr := c.ApplyContext(cCtx.Context, m, wait)
+
+			if output == outputJSON {
+				if results == nil {
+					results = []contract.StepResult{}
+				}
+
+				if err := json.NewEncoder(cCtx.App.Writer).Encode(results); err != nil {
+					return err
+				}
+			} else {
+				w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "step\tstatus\ttx\taddress")
+
+				for _, r := range results {
+					tx, addr := "", ""
+					if r.TxHash != (common.Hash{}) {
+						tx = r.TxHash.Hex()
+					}
+
+					if r.Address != (common.Address{}) {
+						addr = formatAddress(cCtx, r.Address)
+					}
+
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.ID, r.Status, tx, addr)
+				}
+
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+
+			if applyErr != nil {
+				return failure(applyErr, "unable to apply manifest")
+			}
+
+			return nil
+		},
+	}
+}
+
+// buildFlags are shared by the build-tx subcommands.
+func buildFlags(flags ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "`ADDRESS` (0x hex or evmos1 bech32) of the account signing the transaction offline",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "`FILE` the unsigned transaction is written to, stdout when empty",
+		},
+	}, flags...)
+}
+
+func buildTxCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:  "build-tx",
+		Usage: "Build an unsigned transaction file to be signed offline with sign and sent with broadcast",
+		Description: "The nonce, gas limit and fees are taken from the node for the --from account, like when the " +
+			"transaction is sent directly, so it has to be signed and broadcast before that account sends another one.",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "transfer",
+				Usage: "Build a transfer of tokens to the recipient",
+				Flags: buildFlags(
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "recipient `ADDRESS` (0x hex or evmos1 bech32)",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "amount",
+						Usage:    "`AMOUNT` of tokens, eg. 12.5 or \"12.5 GLD\", in base units with --raw",
+						Required: true,
+					},
+					rawFlag(),
+				),
+				Before: rejectArgs,
+				Action: func(cCtx *cli.Context) error {
+					return buildAction(cCtx, c, "to", func(from common.Address, instance contract.IGoldcoin, to string, amount string) (*contract.UnsignedTx, error) {
+						return c.BuildTransferContext(cCtx.Context, from, instance, to, amount)
+					})
+				},
+			},
+			{
+				Name:  "approve",
+				Usage: "Build an approval allowing the spender to transfer up to the amount of tokens",
+				Flags: buildFlags(
+					&cli.StringFlag{
+						Name:     "spender",
+						Usage:    "`ADDRESS` (0x hex or evmos1 bech32) allowed to spend the tokens",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "amount",
+						Usage:    "`AMOUNT` of tokens, eg. 12.5 or \"12.5 GLD\", in base units with --raw",
+						Required: true,
+					},
+					rawFlag(),
+				),
+				Before: rejectArgs,
+				Action: func(cCtx *cli.Context) error {
+					return buildAction(cCtx, c, "spender", func(from common.Address, instance contract.IGoldcoin, spender string, amount string) (*contract.UnsignedTx, error) {
+						return c.BuildApproveContext(cCtx.Context, from, instance, spender, amount)
+					})
+				},
+			},
+			{
+				Name:      "deploy",
+				Usage:     "Build the deployment of the goldcoin smart contract, or any compiled contract",
+				ArgsUsage: "[CONSTRUCTOR ARGUMENTS...]",
+				Description: "The arguments are taken like with deploy, the deployment is recorded in the registry once " +
+					"the signed transaction is broadcast.",
+				Flags: buildFlags(
+					&cli.StringFlag{
+						Name:  "abi",
+						Usage: "`FILE` with the JSON ABI of the contract, eg. abi/Goldcoin.abi",
+					},
+					&cli.StringFlag{
+						Name:  "bin",
+						Usage: "`FILE` with the hex creation bytecode of the contract, eg. bin/Goldcoin.bin",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "`NAME` the deployment is recorded under, defaults to the ABI file name without extension",
+					},
+				),
+				Before: func(cCtx *cli.Context) error {
+					if cCtx.IsSet("abi") {
+						return nil
+					}
+
+					return rejectArgs(cCtx)
+				},
+				Action: func(cCtx *cli.Context) error {
+					from, err := contract.ParseAddress(cCtx.String("from"))
+					if err != nil {
+						return failure(err, "invalid sender")
+					}
+
+					artifact, err := loadArtifact(cCtx)
+					if err != nil {
+						return err
+					}
+
+					if artifact == nil {
+						artifact = contract.GoldcoinArtifact()
+					}
+
+					u, err := c.BuildDeployContext(cCtx.Context, from, artifact, cCtx.Args().Slice()...)
+					if err != nil {
+						return failure(err, "unable to build deployment")
+					}
+
+					log.Info().Msgf("Address: %s", formatAddress(cCtx, u.Deployment.Address))
+
+					return writeTxFile(cCtx, u)
+				},
+			},
+		},
+	}
+}
+
+// buildAction returns the action of a build-tx subcommand building a token transaction to the address in the named
+// flag with build.
+func buildAction(cCtx *cli.Context, c *contract.Contract, flag string, build func(common.Address, contract.IGoldcoin, string, string) (*contract.UnsignedTx, error)) error {
+	from, err := contract.ParseAddress(cCtx.String("from"))
+	if err != nil {
+		return failure(err, "invalid sender")
+	}
+
+	to, err := contract.ParseAddress(cCtx.String(flag))
+	if err != nil {
+		return failure(err, "invalid "+flag)
+	}
+
+	instance, err := c.LoadContext(cCtx.Context)
+	if err != nil {
+		return failure(err, "unable to load contract")
+	}
+
+	amount, err := tokenAmount(cCtx, c, instance)
+	if err != nil {
+		return err
+	}
+
+	u, err := build(from, instance, to.Hex(), amount.String())
+	if err != nil {
+		return failure(err, "unable to build transaction")
+	}
+
+	return writeTxFile(cCtx, u)
+}
+
+func signCommand(signer func(*cli.Context) (contract.Signer, error)) *cli.Command {
+	return &cli.Command{
+		Name:      "sign",
+		Usage:     "Sign a transaction file made by build-tx with the configured signer, without a node",
+		ArgsUsage: "UNSIGNED_FILE",
+		Description: "The transaction is checked to be sent from the signer's account and its parameters are logged " +
+			"for review, the signed transaction is written as JSON, see broadcast.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "`FILE` the signed transaction is written to, stdout when empty",
+			},
+		},
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return usageError("expected the unsigned transaction file (see --help)")
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			data, err := os.ReadFile(cCtx.Args().First())
+			if err != nil {
+				return cli.Exit(fmt.Errorf("unable to read transaction: %w", err), exitUsage)
+			}
+
+			u, err := contract.ParseUnsignedTx(data)
+			if err != nil {
+				return failure(err, "unable to read transaction")
+			}
+
+			s, err := signer(cCtx)
+			if err != nil {
+				return err
+			}
+
+			to := "contract creation"
+			if u.To != nil {
+				to = formatAddress(cCtx, *u.To)
+			}
+
+			log.Info().Msgf("Chain: %d, from: %s, to: %s, nonce: %d, gas: %d, value: %s", u.ChainID, formatAddress(cCtx, u.From), to, u.Nonce, u.Gas, u.Value)
+
+			signed, err := contract.Sign(u, s)
+			if err != nil {
+				return failure(err, "unable to sign transaction")
+			}
+
+			log.Info().Msgf("TXHash: %s", signed.Hash.Hex())
+
+			return writeTxFile(cCtx, signed)
+		},
+	}
+}
+
+func broadcastCommand(c *contract.Contract) *cli.Command {
+	return &cli.Command{
+		Name:      "broadcast",
+		Usage:     "Send a transaction signed offline and wait until it is mined",
+		ArgsUsage: "SIGNED_FILE",
+		Description: "The file is either written by sign or holds a 0x hex raw transaction signed by another tool. " +
+			"The node is checked to be on the chain the transaction is signed for before it is sent.",
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "wait",
+				Usage: "wait until the transaction is mined and fail if it reverted, --wait=false returns once it is sent",
+				Value: true,
+			},
+		}, waitFlags()[1:]...),
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return usageError("expected the signed transaction file (see --help)")
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			data, err := os.ReadFile(cCtx.Args().First())
+			if err != nil {
+				return cli.Exit(fmt.Errorf("unable to read transaction: %w", err), exitUsage)
+			}
+
+			signed, err := contract.ParseSignedTx(data)
+			if err != nil {
+				return failure(err, "unable to read transaction")
+			}
+
+			tx, err := c.BroadcastContext(cCtx.Context, signed)
+			if err != nil {
+				return failure(err, "transaction failed")
+			}
+
+			log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+			if signed.Deployment != nil {
+				log.Info().Msgf("Address: %s", formatAddress(cCtx, signed.Deployment.Address))
+			}
+
+			reciept, err := waitMined(cCtx, c, tx.Hash())
+			if err != nil || reciept == nil || signed.Deployment == nil {
+				return err
+			}
+
+			if err := c.RecordMined(cCtx.Context, signed.Deployment.Name, reciept); err != nil {
+				log.Err(err).Msg("unable to update deployment block number in registry")
+			}
+
+			return nil
+		},
+	}
+}
+
+func speedUpCommand(c *contract.Contract) *cli.Command {
+	return replaceCommand(c, "speedup", "Resend a pending transaction of the owner address with higher fees", c.SpeedUpContext)
+}
+
+func cancelCommand(c *contract.Contract) *cli.Command {
+	return replaceCommand(c, "cancel", "Replace a pending transaction of the owner address with a transfer of nothing to itself", c.CancelContext)
+}
+
+// replaceCommand sends the transaction built by replace at the nonce of a pending transaction, and reports which
+// of the two is mined.
+func replaceCommand(c *contract.Contract, name, usage string, replace func(context.Context, common.Hash) (*types.Transaction, error)) *cli.Command {
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		ArgsUsage: "TX_HASH",
+		Description: fmt.Sprintf("The fees of the transaction are raised by %d%%, or to the current suggested fees when higher, "+
+			"so the node accepts it as a replacement at the same nonce. Only one of the original and the replacement can be "+
+			"mined, the command waits for either and reports which one it is.", contract.ReplacementBump),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "wait",
+				Usage: "wait until either transaction is mined, --wait=false returns once the replacement is sent",
+				Value: true,
+			},
+		}, waitFlags()[1:]...),
+		Before: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return usageError("expected the hash of the pending transaction (see --help)")
+			}
+
+			if b, err := hexutil.Decode(cCtx.Args().First()); err != nil || len(b) != common.HashLength {
+				return usageError("invalid transaction hash %q, expected 0x and 64 hex digits", cCtx.Args().First())
+			}
+
+			return nil
+		},
+		Action: func(cCtx *cli.Context) error {
+			original := common.HexToHash(cCtx.Args().First())
+
+			tx, err := replace(cCtx.Context, original)
+			if err != nil {
+				return failure(err, "unable to replace transaction")
+			}
+
+			log.Info().Msgf("TXHash: %v", tx.Hash().String())
+
+			if !cCtx.Bool("wait") {
+				return nil
+			}
+
+			log.Info().Msgf("Waiting for %s or %s with %d confirmation(s)", original.Hex(), tx.Hash().Hex(), cCtx.Uint64("confirmations"))
+
+			reciept, err := c.WaitAnyMinedContext(cCtx.Context, []common.Hash{original, tx.Hash()}, contract.WaitOpts{
+				Confirmations: cCtx.Uint64("confirmations"),
+				Timeout:       cCtx.Duration("wait-timeout"),
+			})
+			if err != nil {
+				return failure(err, "transaction not confirmed")
+			}
+
+			if reciept.TxHash == original {
+				log.Warn().Msgf("The original %s was mined in block %d, the replacement is dropped", original.Hex(), reciept.BlockNumber)
+				return nil
+			}
+
+			log.Info().Msgf("The replacement %s was mined in block %d, gas used %d", tx.Hash().Hex(), reciept.BlockNumber, reciept.GasUsed)
+
+			return nil
+		},
+	}
+}
+
+// writeTxFile writes the transaction as indented JSON to the file given with `--out`, or stdout.
+func writeTxFile(cCtx *cli.Context, tx interface{}) error {
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return failure(err, "unable to encode transaction")
+	}
+
+	data = append(data, '\n')
+
+	path := cCtx.String("out")
+	if path == "" {
+		_, err = cCtx.App.Writer.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return failure(err, "unable to write transaction")
+	}
+
+	log.Info().Msgf("Written to %s", path)
+
+	return nil
+}
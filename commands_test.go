@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/gopherine/evmos-conploy/address"
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/manifest"
+	"github.com/gopherine/evmos-conploy/registry"
+	"github.com/gopherine/evmos-conploy/signer"
+)
+
+var (
+	testKeyStr = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+	testAddr   = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+	holderAddr = common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d")
+	tokenAddr  = common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+)
+
+func TestExitStatus(t *testing.T) {
+	subtests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Exit code of the command", err: cli.Exit("failed", exitInsufficientBalance), want: exitInsufficientBalance},
+		{name: "Wrapped exit code", err: fmt.Errorf("run: %w", cli.Exit("failed", exitChainMismatch)), want: exitChainMismatch},
+		{name: "Argument parsing error", err: errors.New("flag provided but not defined: -foo"), want: exitUsage},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitStatus(tt.err))
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	subtests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Zero address", err: contract.ErrZeroAddress, want: exitZeroAddress},
+		{name: "Invalid address", err: contract.ErrInvalidAddress, want: exitInvalidAddress},
+		{name: "Address error", err: &contract.AddressError{Input: "0x1234", Err: contract.ErrInvalidAddress}, want: exitInvalidAddress},
+		{name: "Negative amount", err: contract.ErrNegativeAmount, want: exitNegativeAmount},
+		{name: "Invalid amount", err: contract.ErrInvalidAmount, want: exitInvalidAmount},
+		{name: "Insufficient balance", err: contract.ErrInsufficientBalance, want: exitInsufficientBalance},
+		{name: "Insufficient allowance", err: contract.ErrInsufficientAllowance, want: exitInsufficientAllowance},
+		{name: "Chain mismatch", err: contract.ErrChainMismatch, want: exitChainMismatch},
+		{name: "Invalid argument", err: contract.ErrInvalidArgument, want: exitUsage},
+		{name: "Invalid manifest", err: manifest.ErrInvalid, want: exitUsage},
+		{name: "Invalid transaction file", err: contract.ErrInvalidTransaction, want: exitUsage},
+		{name: "Invalid distribution", err: contract.ErrInvalidDistribution, want: exitUsage},
+		{name: "Any other error", err: errors.New("connection refused"), want: exitFailure},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(fmt.Errorf("wrapped: %w", tt.err)))
+			assert.Equal(t, tt.want, exitStatus(failure(tt.err, "command failed")))
+		})
+	}
+}
+
+func TestApp(t *testing.T) {
+	// the cli must return the error of a failed command instead of exiting, so main releases what it opened
+	exiter := cli.OsExiter
+	cli.OsExiter = func(code int) { t.Fatalf("cli exited with code %d", code) }
+	t.Cleanup(func() { cli.OsExiter = exiter })
+
+	owner, err := signer.FromHex(testKeyStr)
+	require.NoError(t, err)
+
+	reg, err := registry.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { reg.Close() })
+
+	require.NoError(t, reg.Put(&registry.Record{
+		ChainID: 9000, Name: contract.GoldcoinName, Address: tokenAddr, TxHash: common.HexToHash("0x01"), BlockNumber: 7,
+		ABI: []byte(goldcoin.GoldcoinABI),
+	}))
+
+	// the token answers every call with these results, by method name
+	results := map[string]interface{}{
+		"name":        "Goldcoin",
+		"symbol":      "GLD",
+		"decimals":    uint8(2),
+		"totalSupply": big.NewInt(100000),
+		"balanceOf":   big.NewInt(0),
+	}
+
+	parsed := contract.GoldcoinArtifact().ABI
+
+	newMock := func(t *testing.T) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(t))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(9000), nil).AnyTimes()
+		m.EXPECT().CodeAt(gomock.Any(), tokenAddr, gomock.Any()).Return([]byte{0x60}, nil).AnyTimes()
+		// no other contract is deployed, not even the deterministic deployer
+		m.EXPECT().CodeAt(gomock.Any(), gomock.Not(tokenAddr), gomock.Any()).Return(nil, nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(100)}, nil).AnyTimes()
+		m.EXPECT().SuggestGasTipCap(gomock.Any()).Return(big.NewInt(7), nil).AnyTimes()
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).Return(uint64(4), nil).AnyTimes()
+		m.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+			method, err := parsed.MethodById(call.Data[:4])
+			require.NoError(t, err)
+
+			return method.Outputs.Pack(results[method.Name])
+		}).AnyTimes()
+
+		return m
+	}
+
+	holder := holderAddr.Hex()
+
+	subtests := []struct {
+		name        string
+		args        []string
+		connectErr  error
+		wantCode    int
+		wantOut     string
+		wantConnect bool
+	}{
+		{
+			name:    "Converts addresses without a node",
+			args:    []string{"address", testAddr.Hex()},
+			wantOut: "hex                                         bech32\n" + testAddr.Hex() + "  " + address.ToBech32(testAddr) + "\n",
+		},
+		{
+			name:    "Prints addresses as JSON",
+			args:    []string{"address", "--output", "json", address.ToBech32(testAddr)},
+			wantOut: `[{"hex":"` + testAddr.Hex() + `","bech32":"` + address.ToBech32(testAddr) + `"}]` + "\n",
+		},
+		{
+			name:    "Prints addresses in the selected format",
+			args:    []string{"--address-format", "bech32", "precompute", "--salt", "0x01"},
+			wantOut: address.ToBech32(contract.Create2Address(common.HexToHash("0x01"), common.FromHex(goldcoin.GoldcoinBin))) + "\n",
+		},
+		{
+			name:        "Prints the token info",
+			args:        []string{"info", "--output", "json"},
+			wantOut:     `"name":"Goldcoin","symbol":"GLD","decimals":2,"totalSupply":100000,"chainId":9000`,
+			wantConnect: true,
+		},
+		{
+			name:     "Rejects unknown address formats",
+			args:     []string{"--address-format", "eip55", "address", testAddr.Hex()},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Rejects positional arguments",
+			args:     []string{"info", "extra"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Rejects missing required flags",
+			args:     []string{"transfer", "--amount", "1"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Rejects unknown flags",
+			args:     []string{"info", "--foo"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Invalid address",
+			args:     []string{"address", "0x1234"},
+			wantCode: exitInvalidAddress,
+		},
+		{
+			name:        "Zero address",
+			args:        []string{"transfer", "--to", common.Address{}.Hex(), "--amount", "1", "--raw"},
+			wantCode:    exitZeroAddress,
+			wantConnect: true,
+		},
+		{
+			name:        "Invalid amount",
+			args:        []string{"transfer", "--to", holder, "--amount", "ten", "--raw"},
+			wantCode:    exitInvalidAmount,
+			wantConnect: true,
+		},
+		{
+			name:        "Negative amount",
+			args:        []string{"transfer", "--to", holder, "--amount=-1", "--raw"},
+			wantCode:    exitNegativeAmount,
+			wantConnect: true,
+		},
+		{
+			name:        "Insufficient balance",
+			args:        []string{"transfer", "--to", holder, "--amount", "1.5"},
+			wantCode:    exitInsufficientBalance,
+			wantConnect: true,
+		},
+		{
+			name:        "Ensures the deployer only for CREATE2 deployments",
+			args:        []string{"deploy", "--ensure-deployer"},
+			wantCode:    exitUsage,
+			wantConnect: true,
+		},
+		{
+			name:        "Does not fund the deployer without opting in",
+			args:        []string{"deploy", "--salt", "0x01"},
+			wantCode:    exitFailure,
+			wantConnect: true,
+		},
+		{
+			name:        "Connection failure",
+			args:        []string{"info"},
+			connectErr:  cli.Exit("client connection failed", exitFailure),
+			wantCode:    exitFailure,
+			wantConnect: true,
+		},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connected, closed int
+
+			connect := func(_ *cli.Context, c *contract.Contract) (func(), error) {
+				connected++
+				if tt.connectErr != nil {
+					return nil, tt.connectErr
+				}
+
+				*c = *contract.NewContract(newMock(t), contract.WithRegistry(reg), contract.WithSigner(owner))
+
+				return func() { closed++ }, nil
+			}
+
+			noSigner := func(*cli.Context) (contract.Signer, error) {
+				return nil, cli.Exit(contract.ErrNoSigner, exitUsage)
+			}
+
+			var out bytes.Buffer
+			app := newApp(&contract.Contract{}, connect, noSigner)
+			app.Writer, app.ErrWriter = &out, io.Discard
+
+			err := app.RunContext(context.Background(), append([]string{"conploy"}, tt.args...))
+
+			if tt.wantCode == 0 {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, exitStatus(err), err.Error())
+			}
+
+			if tt.wantOut != "" {
+				assert.Contains(t, out.String(), tt.wantOut)
+			}
+
+			if tt.wantConnect {
+				assert.Equal(t, 1, connected)
+			} else {
+				assert.Zero(t, connected, "offline and rejected commands do not connect")
+			}
+
+			// whatever connect opened is released, whether the command succeeded or not
+			assert.Equal(t, connected-boolToInt(tt.connectErr != nil), closed)
+		})
+	}
+
+	t.Run("Writes the token info as JSON", func(t *testing.T) {
+		connect := func(_ *cli.Context, c *contract.Contract) (func(), error) {
+			*c = *contract.NewContract(newMock(t), contract.WithRegistry(reg))
+			return func() {}, nil
+		}
+
+		var out bytes.Buffer
+		app := newApp(&contract.Contract{}, connect, nil)
+		app.Writer = &out
+
+		require.NoError(t, app.RunContext(context.Background(), []string{"conploy", "info", "--output", "json"}))
+
+		var info contract.TokenInfo
+		require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+		assert.Equal(t, tokenAddr, info.Address)
+		assert.Equal(t, uint64(7), info.DeployBlock)
+	})
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,371 @@
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+const (
+	// DefaultSolc is the solc binary used when none is configured, it is looked up in PATH
+	DefaultSolc = "solc"
+	// DefaultIncludePath is where the npm dependencies of the contracts, eg. `@openzeppelin/contracts`, are installed
+	DefaultIncludePath = "solidity-contracts/node_modules"
+	// DefaultRuns is the number of runs the optimizer tunes for when it is enabled without a number
+	DefaultRuns = 200
+)
+
+// ErrCompile is matched by every `*CompileError`
+var ErrCompile = errors.New("compilation failed")
+
+// Solc compiles Solidity sources with a solc binary through its standard JSON interface.
+type Solc struct {
+	// Path is the solc binary, defaults to `DefaultSolc`
+	Path string
+	// BasePath is the directory sources and imports are resolved from, defaults to the working directory
+	BasePath string
+	// IncludePaths are searched for imports not found under the base path, defaults to `DefaultIncludePath`
+	IncludePaths []string
+	Optimize     bool
+	// Runs is the optimizer runs setting, defaults to `DefaultRuns`
+	Runs int
+	// EVMVersion is the target EVM version, the default of the solc binary when empty
+	EVMVersion string
+}
+
+// Artifact is a contract compiled from one of the requested sources.
+type Artifact struct {
+	Name string
+	// Source is the source file the contract is declared in, relative to the base path
+	Source string
+	ABI    json.RawMessage
+	// Bin is the hex creation bytecode without 0x prefix, empty for interfaces and abstract contracts
+	Bin string
+	// Metadata is the solc metadata JSON of the contract
+	Metadata string
+	// Signatures maps the method signatures to their 4 byte selector
+	Signatures map[string]string
+}
+
+// Diagnostic is an error or warning reported by solc.
+type Diagnostic struct {
+	// Severity is error, warning or info
+	Severity string `json:"severity"`
+	// Type is the solc error type, eg. ParserError, TypeError or DeclarationError
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	// Line and Column are 1 based, zero when solc reported no location
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Formatted is the message as printed by solc, with the offending source line
+	Formatted string `json:"formatted,omitempty"`
+}
+
+// String prints the diagnostic as file:line:column: severity: message.
+func (d Diagnostic) String() string {
+	loc := ""
+	if d.File != "" {
+		loc = fmt.Sprintf("%s:%d:%d: ", d.File, d.Line, d.Column)
+	}
+
+	return fmt.Sprintf("%s%s: %s: %s", loc, d.Severity, d.Type, d.Message)
+}
+
+// CompileError is returned when solc reports errors, `Diagnostics` holds every error and warning reported.
+type CompileError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *CompileError) Error() string {
+	var errs []string
+	for _, d := range e.Diagnostics {
+		if d.Severity == "error" {
+			errs = append(errs, d.String())
+		}
+	}
+
+	return fmt.Sprintf("%s with %d error(s): %s", ErrCompile, len(errs), strings.Join(errs, "; "))
+}
+
+// Is reports every compile error as `ErrCompile`.
+func (e *CompileError) Is(target error) bool {
+	return target == ErrCompile
+}
+
+// Result holds the contracts declared in the compiled sources and the warnings reported while compiling them.
+type Result struct {
+	Artifacts []*Artifact
+	Warnings  []Diagnostic
+}
+
+// input and output are the parts of the solc standard JSON used, see
+// https://docs.soliditylang.org/en/latest/using-the-compiler.html#compiler-input-and-output-json-description
+type input struct {
+	Language string                       `json:"language"`
+	Sources  map[string]map[string]string `json:"sources"`
+	Settings settings                     `json:"settings"`
+}
+
+type settings struct {
+	Optimizer struct {
+		Enabled bool `json:"enabled"`
+		Runs    int  `json:"runs"`
+	} `json:"optimizer"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+type output struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		Type             string `json:"type"`
+		Message          string `json:"message"`
+		FormattedMessage string `json:"formattedMessage"`
+		SourceLocation   *struct {
+			File  string `json:"file"`
+			Start int    `json:"start"`
+		} `json:"sourceLocation"`
+	} `json:"errors"`
+	Contracts map[string]map[string]struct {
+		ABI      json.RawMessage `json:"abi"`
+		Metadata string          `json:"metadata"`
+		EVM      struct {
+			Bytecode struct {
+				Object string `json:"object"`
+			} `json:"bytecode"`
+			MethodIdentifiers map[string]string `json:"methodIdentifiers"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// Compile compiles the given source files, relative to the base path, and returns the contracts declared in them.
+// Contracts only imported by the sources are compiled but not returned. Errors reported by solc are returned as
+// `*CompileError`.
+func (s *Solc) Compile(ctx context.Context, sources ...string) (*Result, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no source files given")
+	}
+
+	in := input{Language: "Solidity", Sources: make(map[string]map[string]string, len(sources))}
+	in.Settings.Optimizer.Enabled = s.Optimize
+	in.Settings.Optimizer.Runs = s.runs()
+	in.Settings.EVMVersion = s.EVMVersion
+	in.Settings.OutputSelection = map[string]map[string][]string{
+		"*": {"*": {"abi", "metadata", "evm.bytecode.object", "evm.methodIdentifiers"}},
+	}
+
+	for _, src := range sources {
+		key := filepath.ToSlash(filepath.Clean(src))
+
+		content, err := os.ReadFile(filepath.Join(s.BasePath, key))
+		if err != nil {
+			return nil, err
+		}
+
+		in.Sources[key] = map[string]string{"content": string(content)}
+	}
+
+	out, err := s.run(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	var diagnostics []Diagnostic
+	failed := false
+
+	for _, e := range out.Errors {
+		d := Diagnostic{Severity: e.Severity, Type: e.Type, Message: e.Message, Formatted: e.FormattedMessage}
+		if e.SourceLocation != nil {
+			d.File = e.SourceLocation.File
+			d.Line, d.Column = s.position(d.File, e.SourceLocation.Start)
+		}
+
+		diagnostics = append(diagnostics, d)
+
+		switch d.Severity {
+		case "error":
+			failed = true
+		case "warning":
+			result.Warnings = append(result.Warnings, d)
+		}
+	}
+
+	if failed {
+		return nil, &CompileError{Diagnostics: diagnostics}
+	}
+
+	keys := make([]string, 0, len(in.Sources))
+	for key := range in.Sources {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		contracts := out.Contracts[key]
+
+		names := make([]string, 0, len(contracts))
+		for name := range contracts {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			c := contracts[name]
+
+			result.Artifacts = append(result.Artifacts, &Artifact{
+				Name:       name,
+				Source:     key,
+				ABI:        c.ABI,
+				Bin:        c.EVM.Bytecode.Object,
+				Metadata:   c.Metadata,
+				Signatures: c.EVM.MethodIdentifiers,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// run passes the input to solc and decodes its output.
+func (s *Solc) run(ctx context.Context, in input) (*output, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	path := s.Path
+	if path == "" {
+		path = DefaultSolc
+	}
+
+	base := s.BasePath
+	if base == "" {
+		base = "."
+	}
+
+	args := []string{"--standard-json", "--base-path", base}
+	for _, p := range s.includePaths() {
+		args = append(args, "--include-path", p)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	// compile errors are reported in the output with a zero exit status, a failure is an unusable binary or input
+	runErr := cmd.Run()
+
+	out := new(output)
+	if err := json.Unmarshal(stdout.Bytes(), out); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("%s: %w: %s", path, runErr, strings.TrimSpace(stderr.String()))
+		}
+
+		return nil, fmt.Errorf("%s: unreadable output: %w", path, err)
+	}
+
+	return out, nil
+}
+
+func (s *Solc) includePaths() []string {
+	if s.IncludePaths == nil {
+		return []string{DefaultIncludePath}
+	}
+
+	return s.IncludePaths
+}
+
+func (s *Solc) runs() int {
+	if s.Runs == 0 {
+		return DefaultRuns
+	}
+
+	return s.Runs
+}
+
+// position converts a byte offset of a source file into a line and column, zero when the file can not be read.
+func (s *Solc) position(file string, offset int) (int, int) {
+	content, err := os.ReadFile(filepath.Join(s.BasePath, file))
+	if err != nil {
+		for _, p := range s.includePaths() {
+			if content, err = os.ReadFile(filepath.Join(p, file)); err == nil {
+				break
+			}
+		}
+	}
+
+	if err != nil || offset < 0 || offset > len(content) {
+		return 0, 0
+	}
+
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// Write saves the artifact under dir as `abi/<Name>.abi`, `bin/<Name>.bin` and `metadata/<Name>.json`, the files
+// the `deploy --abi --bin` command and abigen read. It returns the paths written.
+func (a *Artifact) Write(dir string) ([]string, error) {
+	files := []struct {
+		path string
+		data []byte
+	}{
+		{filepath.Join(dir, "abi", a.Name+".abi"), a.ABI},
+		{filepath.Join(dir, "bin", a.Name+".bin"), []byte(a.Bin)},
+		{filepath.Join(dir, "metadata", a.Name+".json"), []byte(a.Metadata)},
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		if err := writeFile(f.path, f.data); err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, f.path)
+	}
+
+	return paths, nil
+}
+
+// WriteBinding generates the Go binding of the artifact, like abigen does, into `<pkg>/<pkg>.go` under dir. The
+// package defaults to the lowercased contract name, eg. package goldcoin for Goldcoin. It returns the path written.
+func (a *Artifact) WriteBinding(dir, pkg string) (string, error) {
+	if pkg == "" {
+		pkg = strings.ToLower(a.Name)
+	}
+
+	code, err := bind.Bind([]string{a.Name}, []string{string(a.ABI)}, []string{a.Bin}, []map[string]string{a.Signatures}, pkg, bind.LangGo, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("binding of %s: %w", a.Name, err)
+	}
+
+	path := filepath.Join(dir, pkg, pkg+".go")
+
+	return path, writeFile(path, []byte(code))
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,166 @@
+package compiler_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/compiler"
+)
+
+const vaultSource = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+import "@openzeppelin/contracts/utils/Context.sol";
+
+contract Vault is Context {
+    function get() public view returns (uint256) { return 1 }
+}
+`
+
+const vaultABI = `[{"inputs":[],"name":"get","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// fakeSolc installs a script standing in for solc, it saves its input and arguments next to itself and prints
+// the given standard JSON output.
+func fakeSolc(t *testing.T, output string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output.json"), []byte(output), 0o600))
+
+	script := "#!/bin/sh\ncat > \"$(dirname \"$0\")/input.json\"\necho \"$@\" > \"$(dirname \"$0\")/args\"\ncat \"$(dirname \"$0\")/output.json\"\n"
+	path := filepath.Join(dir, "solc")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700))
+
+	return path
+}
+
+func TestCompile(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "contracts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "contracts", "Vault.sol"), []byte(vaultSource), 0o600))
+
+	t.Run("Returns the contracts of the given sources", func(t *testing.T) {
+		solc := fakeSolc(t, `{
+			"errors": [{"severity": "warning", "type": "Warning", "message": "Unused variable",
+				"sourceLocation": {"file": "contracts/Vault.sol", "start": 142}}],
+			"contracts": {
+				"contracts/Vault.sol": {"Vault": {"abi": `+vaultABI+`, "metadata": "{\"compiler\":{}}",
+					"evm": {"bytecode": {"object": "6080604052"}, "methodIdentifiers": {"get()": "6d4ce63c"}}}},
+				"@openzeppelin/contracts/utils/Context.sol": {"Context": {"abi": [], "evm": {"bytecode": {"object": ""}}}}
+			}
+		}`)
+
+		s := &compiler.Solc{Path: solc, BasePath: base, Optimize: true}
+
+		result, err := s.Compile(context.Background(), "contracts/Vault.sol")
+		require.NoError(t, err)
+		require.Len(t, result.Artifacts, 1)
+
+		vault := result.Artifacts[0]
+		assert.Equal(t, "Vault", vault.Name)
+		assert.Equal(t, "contracts/Vault.sol", vault.Source)
+		assert.Equal(t, "6080604052", vault.Bin)
+		assert.JSONEq(t, vaultABI, string(vault.ABI))
+		assert.Equal(t, map[string]string{"get()": "6d4ce63c"}, vault.Signatures)
+
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, 7, result.Warnings[0].Line)
+		assert.Equal(t, 5, result.Warnings[0].Column)
+
+		// the source is passed inline and imports are resolved from the include path
+		var input struct {
+			Sources  map[string]map[string]string `json:"sources"`
+			Settings struct {
+				Optimizer struct {
+					Enabled bool `json:"enabled"`
+					Runs    int  `json:"runs"`
+				} `json:"optimizer"`
+			} `json:"settings"`
+		}
+
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(solc), "input.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &input))
+		assert.Equal(t, vaultSource, input.Sources["contracts/Vault.sol"]["content"])
+		assert.True(t, input.Settings.Optimizer.Enabled)
+		assert.Equal(t, compiler.DefaultRuns, input.Settings.Optimizer.Runs)
+
+		args, err := os.ReadFile(filepath.Join(filepath.Dir(solc), "args"))
+		require.NoError(t, err)
+		assert.Equal(t, "--standard-json --base-path "+base+" --include-path "+compiler.DefaultIncludePath, strings.TrimSpace(string(args)))
+	})
+
+	t.Run("Reports errors as diagnostics", func(t *testing.T) {
+		solc := fakeSolc(t, `{"errors": [
+			{"severity": "error", "type": "ParserError", "message": "Expected ';' but got '}'",
+				"formattedMessage": "ParserError: Expected ';' but got '}'",
+				"sourceLocation": {"file": "contracts/Vault.sol", "start": 198}},
+			{"severity": "warning", "type": "Warning", "message": "Unreachable code"}
+		]}`)
+
+		_, err := (&compiler.Solc{Path: solc, BasePath: base}).Compile(context.Background(), "contracts/Vault.sol")
+		assert.ErrorIs(t, err, compiler.ErrCompile)
+
+		var compileErr *compiler.CompileError
+		require.ErrorAs(t, err, &compileErr)
+		require.Len(t, compileErr.Diagnostics, 2)
+
+		d := compileErr.Diagnostics[0]
+		assert.Equal(t, "ParserError", d.Type)
+		assert.Equal(t, "contracts/Vault.sol:7:61: error: ParserError: Expected ';' but got '}'", d.String())
+		assert.Contains(t, err.Error(), "1 error(s)")
+	})
+
+	t.Run("Fails when solc can not be run", func(t *testing.T) {
+		_, err := (&compiler.Solc{Path: filepath.Join(base, "missing"), BasePath: base}).Compile(context.Background(), "contracts/Vault.sol")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, compiler.ErrCompile)
+	})
+
+	t.Run("Missing source", func(t *testing.T) {
+		_, err := (&compiler.Solc{BasePath: base}).Compile(context.Background(), "contracts/Missing.sol")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestWriteArtifact(t *testing.T) {
+	dir := t.TempDir()
+	vault := &compiler.Artifact{
+		Name:       "Vault",
+		ABI:        []byte(vaultABI),
+		Bin:        "6080604052",
+		Metadata:   `{"compiler":{}}`,
+		Signatures: map[string]string{"get()": "6d4ce63c"},
+	}
+
+	paths, err := vault.Write(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "abi", "Vault.abi"),
+		filepath.Join(dir, "bin", "Vault.bin"),
+		filepath.Join(dir, "metadata", "Vault.json"),
+	}, paths)
+
+	bin, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, "6080604052", string(bin))
+
+	path, err := vault.WriteBinding(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "vault", "vault.go"), path)
+
+	code, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "package vault")
+	assert.Contains(t, string(code), "func DeployVault(")
+	assert.Contains(t, string(code), `"6d4ce63c": "get()"`)
+
+	path, err = vault.WriteBinding(dir, "store")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "store", "store.go"), path)
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the config file read when none is given, it is optional
+const DefaultPath = "conploy.yaml"
+
+// ErrUnknownNetwork is returned when the selected network has no profile
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// ErrNoRPC is returned when a node is needed but no network, rpc url or CLIENT_URL is configured
+var ErrNoRPC = errors.New("no rpc url configured, select a network or set CLIENT_URL")
+
+// Config holds the network profiles, eg.
+//
+//	network: local
+//	networks:
+//	  local:
+//	    rpc: [http://localhost:8545]
+//	    chain_id: 9000
+//	  staging:
+//	    rpc: [https://rpc-1.example.org, https://rpc-2.example.org]
+//	    chain_id: 9000
+//	    registry: staging
+//	    signer: {keystore: keys/staging.json, password_file: keys/staging.pass}
+//	    gas: {multiplier: 1.5, cap: 8000000}
+//
+// Profiles extend the built-in ones, `Defaults`, and replace those with the same name.
+type Config struct {
+	// Network is the profile used when none is selected with `--network`
+	Network  string              `yaml:"network"`
+	Networks map[string]*Network `yaml:"networks"`
+}
+
+// Network is the profile of a chain conploy is pointed at.
+type Network struct {
+	// RPC are the node URLs, tried in order until one answers
+	RPC []string `yaml:"rpc"`
+	// ChainID is the chain the node is checked against before anything is sent, not checked when zero
+	ChainID uint64 `yaml:"chain_id"`
+	// Signer is the account transactions are sent from, the signer set in the environment when empty
+	Signer Signer `yaml:"signer"`
+	// Gas overrides the gas policy set in the environment
+	Gas Gas `yaml:"gas"`
+	// Registry is the namespace deployments and manifest progress are recorded under, a subdirectory of the
+	// registry, so networks sharing a chain id, eg. a local node and the testnet, do not mix their records
+	Registry string `yaml:"registry"`
+}
+
+// Signer references the key transactions are signed with, secrets are never written in the config itself but
+// read from files or environment variables. A keystore takes precedence over a mnemonic which takes precedence
+// over a raw private key.
+type Signer struct {
+	// Keystore is an encrypted (v3) keystore file, its passphrase is read from PasswordFile or prompted for
+	Keystore     string `yaml:"keystore"`
+	PasswordFile string `yaml:"password_file"`
+	// MnemonicFile and MnemonicEnv hold a BIP-39 mnemonic derived at DerivationPath
+	MnemonicFile   string `yaml:"mnemonic_file"`
+	MnemonicEnv    string `yaml:"mnemonic_env"`
+	PassphraseEnv  string `yaml:"passphrase_env"`
+	DerivationPath string `yaml:"derivation_path"`
+	// PrivateKeyEnv is the environment variable holding a hex private key
+	PrivateKeyEnv string `yaml:"private_key_env"`
+}
+
+// IsZero reports whether no signer is referenced.
+func (s Signer) IsZero() bool {
+	return s == Signer{}
+}
+
+// Gas configures the gas policy of the transactions sent, zero values keep the policy set in the environment.
+type Gas struct {
+	// Legacy sends legacy transactions instead of dynamic fee ones
+	Legacy     bool    `yaml:"legacy"`
+	Multiplier float64 `yaml:"multiplier"`
+	Cap        uint64  `yaml:"cap"`
+}
+
+// Defaults are the built-in profiles: a local evmosd node and the public evmos testnet and mainnet endpoints.
+// The local profile keeps the registry namespace of records made before profiles existed.
+func Defaults() map[string]*Network {
+	return map[string]*Network{
+		"local":   {RPC: []string{"http://localhost:8545"}, ChainID: 9000},
+		"testnet": {RPC: []string{"https://eth.bd.evmos.dev:8545"}, ChainID: 9000, Registry: "testnet"},
+		"mainnet": {RPC: []string{"https://eth.bd.evmos.org:8545"}, ChainID: 9001, Registry: "mainnet"},
+	}
+}
+
+// Load reads a YAML or JSON config file, unknown fields are rejected. A missing file is only an error when
+// required is set, the built-in profiles are returned otherwise.
+func Load(path string, required bool) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !required:
+	case err != nil:
+		return nil, err
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+
+		if err := dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	networks := Defaults()
+	for name, n := range cfg.Networks {
+		if n == nil || len(n.RPC) == 0 {
+			return nil, fmt.Errorf("%s: network %s has no rpc url", path, name)
+		}
+
+		networks[name] = n
+	}
+
+	cfg.Networks = networks
+
+	return cfg, nil
+}
+
+// Select returns the named profile, or the default one of the config when name is empty. Without either the
+// profile set in the environment, `FromEnv`, is returned.
+func (c *Config) Select(name string) (*Network, error) {
+	if name == "" {
+		name = c.Network
+	}
+
+	if name == "" {
+		return FromEnv(), nil
+	}
+
+	n, ok := c.Networks[name]
+	if !ok {
+		names := make([]string, 0, len(c.Networks))
+		for n := range c.Networks {
+			names = append(names, n)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownNetwork, name, strings.Join(names, ", "))
+	}
+
+	return n, nil
+}
+
+// FromEnv returns the profile described by the .env variables used before profiles existed: the node at
+// CLIENT_URL on any chain, or CHAIN_ID when set.
+func FromEnv() *Network {
+	chainID, _ := strconv.ParseUint(os.Getenv("CHAIN_ID"), 10, 64)
+
+	n := &Network{ChainID: chainID}
+	if url := os.Getenv("CLIENT_URL"); url != "" {
+		n.RPC = []string{url}
+	}
+
+	return n
+}
+
+// Dial connects to the first RPC url of the profile whose node answers, the others are fallbacks.
+func (n *Network) Dial(ctx context.Context) (*ethclient.Client, error) {
+	var errs []string
+
+	for _, url := range n.RPC {
+		client, err := ethclient.DialContext(ctx, url)
+		if err == nil {
+			// http clients only connect on the first call, make sure the node answers before settling on it
+			if _, err = client.ChainID(ctx); err == nil {
+				return client, nil
+			}
+
+			client.Close()
+		}
+
+		log.Warn().Err(err).Msgf("node at %s unavailable", url)
+		errs = append(errs, fmt.Sprintf("%s: %s", url, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, ErrNoRPC
+	}
+
+	return nil, fmt.Errorf("no node available: %s", strings.Join(errs, "; "))
+}
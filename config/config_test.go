@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/config"
+)
+
+const profiles = `
+network: staging
+networks:
+  staging:
+    rpc: [https://rpc-1.example.org, https://rpc-2.example.org]
+    chain_id: 9000
+    registry: staging
+    signer: {keystore: keys/staging.json, password_file: keys/staging.pass}
+    gas: {multiplier: 1.5, cap: 8000000}
+  mainnet:
+    rpc: [https://rpc.example.org]
+    chain_id: 9001
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "conploy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, profiles), true)
+	require.NoError(t, err)
+
+	t.Run("Selects the default network", func(t *testing.T) {
+		n, err := cfg.Select("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://rpc-1.example.org", "https://rpc-2.example.org"}, n.RPC)
+		assert.Equal(t, uint64(9000), n.ChainID)
+		assert.Equal(t, "staging", n.Registry)
+		assert.Equal(t, "keys/staging.json", n.Signer.Keystore)
+		assert.Equal(t, 1.5, n.Gas.Multiplier)
+		assert.Equal(t, uint64(8000000), n.Gas.Cap)
+	})
+
+	t.Run("Profiles replace the built-in ones", func(t *testing.T) {
+		n, err := cfg.Select("mainnet")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://rpc.example.org"}, n.RPC)
+
+		n, err = cfg.Select("local")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"http://localhost:8545"}, n.RPC)
+		assert.True(t, n.Signer.IsZero())
+	})
+
+	t.Run("Unknown network", func(t *testing.T) {
+		_, err := cfg.Select("devnet")
+		assert.ErrorIs(t, err, config.ErrUnknownNetwork)
+		assert.Contains(t, err.Error(), "local, mainnet, staging, testnet")
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "conploy.yaml")
+
+		_, err := config.Load(missing, true)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		cfg, err := config.Load(missing, false)
+		require.NoError(t, err)
+
+		t.Setenv("CLIENT_URL", "http://node:8545")
+		t.Setenv("CHAIN_ID", "9000")
+
+		n, err := cfg.Select("")
+		require.NoError(t, err)
+		assert.Equal(t, &config.Network{RPC: []string{"http://node:8545"}, ChainID: 9000}, n, "the environment is used without a network")
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		_, err := config.Load(writeConfig(t, "networks:\n  local:\n    url: http://localhost:8545\n"), true)
+		assert.Error(t, err)
+
+		_, err = config.Load(writeConfig(t, "networks:\n  local:\n    chain_id: 9000\n"), true)
+		assert.ErrorContains(t, err, "no rpc url")
+	})
+}
+
+func TestDial(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "eth_chainId") {
+			http.Error(w, "unexpected call", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2328"}`))
+	}))
+	defer node.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	n := &config.Network{RPC: []string{down.URL, node.URL}}
+
+	client, err := n.Dial(context.Background())
+	require.NoError(t, err)
+	defer client.Close()
+
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9000), chainID.Uint64(), "the first node answering is used")
+
+	_, err = (&config.Network{RPC: []string{down.URL}}).Dial(context.Background())
+	assert.ErrorContains(t, err, "no node available")
+
+	t.Setenv("CLIENT_URL", "")
+
+	_, err = config.FromEnv().Dial(context.Background())
+	assert.ErrorIs(t, err, config.ErrNoRPC)
+}
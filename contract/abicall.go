@@ -0,0 +1,401 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// Target is a deployed contract along with the ABI its methods are called and its events decoded with.
+type Target struct {
+	// Name is the registry name of the contract, empty when it was given by address and is not in the registry
+	Name    string
+	Address common.Address
+	ABI     abi.ABI
+}
+
+// Value is a decoded return value or event argument, it prints addresses and bytes as 0x hex, integers in
+// base 10 and tuples as objects keyed by component name.
+type Value struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// DecodedEvent is a log emitted by a call, decoded with the ABI of the `Target`.
+type DecodedEvent struct {
+	Name     string         `json:"name"`
+	Address  common.Address `json:"address"`
+	LogIndex uint           `json:"logIndex"`
+	Args     []Value        `json:"args"`
+}
+
+// Resolve looks up the contract the `call` and `send` commands interact with, ref is either a name in the
+// registry or an address given as 0x hex or evmos bech32. The ABI is read from abiPath when it is set and taken
+// from the registry record otherwise.
+func (c *Contract) Resolve(ref string, abiPath string) (*Target, error) {
+	return c.ResolveContext(context.Background(), ref, abiPath)
+}
+
+// ResolveContext is like `Resolve` but the registry is looked up with the given context.
+func (c *Contract) ResolveContext(ctx context.Context, ref string, abiPath string) (*Target, error) {
+	rec, err := c.resolveRecord(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var abiJSON []byte
+
+	switch {
+	case abiPath != "":
+		if abiJSON, err = os.ReadFile(abiPath); err != nil {
+			log.Err(err).Msg("unable to read abi file")
+			return nil, err
+		}
+	case len(rec.ABI) > 0:
+		abiJSON = rec.ABI
+	case rec.Name == GoldcoinName:
+		// goldcoin deployments recorded before the ABI was kept
+		abiJSON = []byte(goldcoin.GoldcoinABI)
+	default:
+		err := fmt.Errorf("%w for %s, pass the ABI file", ErrNoABI, ref)
+		log.Err(err).Msg("unable to resolve contract ABI")
+		return nil, err
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		log.Err(err).Msg("unable to parse contract ABI")
+		return nil, err
+	}
+
+	return &Target{Name: rec.Name, Address: rec.Address, ABI: parsed}, nil
+}
+
+// resolveRecord returns the latest registry record of a name or address, an address missing from the registry
+// is returned as a bare record so it can still be called with an ABI file.
+func (c *Contract) resolveRecord(ctx context.Context, ref string) (*registry.Record, error) {
+	addr, err := ParseAddress(ref)
+	if err != nil {
+		// neither hex nor bech32, anything that looks like an attempt at one is reported as an invalid address
+		if strings.HasPrefix(ref, "0x") || strings.HasPrefix(ref, "evmos1") {
+			log.Err(err).Msg("invalid contract address")
+			return nil, err
+		}
+
+		return c.latestDeployment(ctx, ref)
+	}
+
+	if c.Registry == nil {
+		return &registry.Record{Address: addr}, nil
+	}
+
+	records, err := c.DeploymentsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// records are ordered by deployment time, the latest one with an ABI wins
+	found := &registry.Record{Address: addr}
+	for _, rec := range records {
+		if rec.Address == addr && (len(rec.ABI) > 0 || len(found.ABI) == 0) {
+			found = rec
+		}
+	}
+
+	return found, nil
+}
+
+// Call calls a read-only method of the target with the arguments given as strings, they are converted to the
+// method input types with `ParseArguments`. The call is made from the signer address when a signer is configured.
+func (c *Contract) Call(t *Target, method string, args ...string) ([]Value, error) {
+	return c.CallContext(context.Background(), t, method, args...)
+}
+
+// CallContext is like `Call` but the call is made with the given context.
+func (c *Contract) CallContext(ctx context.Context, t *Target, method string, args ...string) ([]Value, error) {
+	m, params, err := t.pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	if c.Signer != nil {
+		opts.From = c.Signer.Address()
+	}
+
+	var out []interface{}
+
+	bound := bind.NewBoundContract(t.Address, t.ABI, c.Client, c.Client, c.Client)
+	if err := bound.Call(opts, &out, m.Name, params...); err != nil {
+		log.Err(err).Msgf("unable to call %s", m.Sig)
+		return nil, err
+	}
+
+	return values(m.Outputs, out), nil
+}
+
+// Send sends a transaction calling a method of the target, value is the amount of wei sent along and may be nil.
+// Arguments are converted like with `Call`.
+func (c *Contract) Send(t *Target, method string, value *big.Int, args ...string) (*types.Transaction, error) {
+	return c.SendContext(context.Background(), t, method, value, args...)
+}
+
+// SendContext is like `Send` but the transaction is built and sent with the given context.
+func (c *Contract) SendContext(ctx context.Context, t *Target, method string, value *big.Int, args ...string) (*types.Transaction, error) {
+	return c.send(ctx, t, method, value, nil, args...)
+}
+
+// send sends a transaction calling a method of the target, it is kept with save before it is sent when save is
+// given, see `sendSaved`.
+func (c *Contract) send(ctx context.Context, t *Target, method string, value *big.Int, save func(tx *types.Transaction) error, args ...string) (*types.Transaction, error) {
+	m, params, err := t.pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if value != nil && value.Sign() > 0 && !m.IsPayable() {
+		err := fmt.Errorf("%w: %s is not payable", ErrInvalidArgument, m.Sig)
+		log.Err(err).Msg("unable to make transaction")
+		return nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if value != nil {
+		auth.Value = value
+	}
+
+	auth.NoSend = save != nil
+
+	bound := bind.NewBoundContract(t.Address, t.ABI, c.backend(), c.backend(), c.backend())
+
+	tx, err := bound.Transact(auth, m.Name, params...)
+	if err != nil {
+		log.Err(err).Msgf("unable to send %s", m.Sig)
+		return nil, err
+	}
+
+	if err := c.sendSaved(ctx, tx, save); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// pack looks up the method and converts its arguments.
+func (t *Target) pack(method string, args []string) (abi.Method, []interface{}, error) {
+	m, ok := t.ABI.Methods[method]
+	if !ok {
+		names := make([]string, 0, len(t.ABI.Methods))
+		for name := range t.ABI.Methods {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		err := fmt.Errorf("%w: %s, available: %s", ErrUnknownMethod, method, strings.Join(names, ", "))
+		log.Err(err).Msg("unable to resolve method")
+
+		return abi.Method{}, nil, err
+	}
+
+	params, err := ParseArguments(m.Inputs, args)
+	if err != nil {
+		log.Err(err).Msgf("invalid arguments of %s", m.Sig)
+		return abi.Method{}, nil, err
+	}
+
+	return m, params, nil
+}
+
+// DecodeEvents decodes the logs matching an event of the ABI, eg. the logs of a reciept. Logs of other contracts
+// are decoded as well when their event is in the ABI, anonymous events and unknown logs are skipped.
+func (t *Target) DecodeEvents(logs []*types.Log) []DecodedEvent {
+	var events []DecodedEvent
+
+	for _, l := range logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+
+		ev, err := t.ABI.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		args, err := decodeLog(ev, l)
+		if err != nil {
+			log.Err(err).Msgf("unable to decode %s log %d", ev.Name, l.Index)
+			continue
+		}
+
+		events = append(events, DecodedEvent{Name: ev.Name, Address: l.Address, LogIndex: l.Index, Args: args})
+	}
+
+	return events
+}
+
+// decodeLog decodes the arguments of an event in the order they are declared, indexed strings, bytes, arrays
+// and tuples are only known by the hash stored in their topic.
+func decodeLog(ev *abi.Event, l *types.Log) ([]Value, error) {
+	data, err := ev.Inputs.UnpackValues(l.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]Value, 0, len(ev.Inputs))
+	topics := l.Topics[1:]
+
+	for _, input := range ev.Inputs {
+		var v interface{}
+
+		if input.Indexed {
+			if len(topics) == 0 {
+				return nil, fmt.Errorf("missing topic of %s", input.Name)
+			}
+
+			if v, err = topicValue(input, topics[0]); err != nil {
+				return nil, err
+			}
+
+			topics = topics[1:]
+		} else {
+			v, data = data[0], data[1:]
+		}
+
+		args = append(args, Value{Name: input.Name, Type: input.Type.String(), Value: v})
+	}
+
+	return args, nil
+}
+
+// topicValue decodes a single indexed argument.
+func topicValue(input abi.Argument, topic common.Hash) (interface{}, error) {
+	if input.Type.T == abi.TupleTy {
+		return topic, nil
+	}
+
+	// arguments are keyed by name, a single one under a fixed key also covers unnamed arguments
+	input.Name = "value"
+	out := make(map[string]interface{}, 1)
+	if err := abi.ParseTopicsIntoMap(out, abi.Arguments{input}, []common.Hash{topic}); err != nil {
+		return nil, err
+	}
+
+	return out["value"], nil
+}
+
+// values pairs unpacked return values with their ABI arguments.
+func values(outputs abi.Arguments, out []interface{}) []Value {
+	vals := make([]Value, 0, len(out))
+	for i, v := range out {
+		vals = append(vals, Value{Name: outputs[i].Name, Type: outputs[i].Type.String(), Value: v})
+	}
+
+	return vals
+}
+
+// String prints the value, strings as is and any other type in its JSON form.
+func (v Value) String() string {
+	p := printable(v.Value)
+	if s, ok := p.(string); ok {
+		return s
+	}
+
+	out, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Sprint(v.Value)
+	}
+
+	return string(out)
+}
+
+// MarshalJSON prints the value in its printable form.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string      `json:"name,omitempty"`
+		Type  string      `json:"type"`
+		Value interface{} `json:"value"`
+	}{v.Name, v.Type, printable(v.Value)})
+}
+
+var (
+	addressType = reflect.TypeOf(common.Address{})
+	hashType    = reflect.TypeOf(common.Hash{})
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+)
+
+// printable converts the Go types values are unpacked to into ones printing the way they are given as arguments.
+func printable(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	switch rv.Type() {
+	case addressType:
+		return rv.Interface().(common.Address).Hex()
+	case hashType:
+		return rv.Interface().(common.Hash).Hex()
+	case bigIntType:
+		return rv.Interface().(*big.Int).String()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+
+			return hexutil.Encode(b)
+		}
+
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = printable(rv.Index(i).Interface())
+		}
+
+		return list
+	case reflect.Struct:
+		// tuples are unpacked to anonymous structs whose json tags hold the component names
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+
+			name := f.Tag.Get("json")
+			if name == "" {
+				name = f.Name
+			}
+
+			fields[name] = printable(rv.Field(i).Interface())
+		}
+
+		return fields
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// printed as strings like big integers, JSON numbers lose precision above 2^53
+		return fmt.Sprint(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,188 @@
+package contract_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/address"
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// ledgerABI has methods taking and returning tuples, arrays and bytes along with an event with indexed arguments
+const ledgerABI = `[
+	{"type":"function","name":"get","stateMutability":"view",
+		"inputs":[{"name":"id","type":"uint256"}],
+		"outputs":[{"name":"entry","type":"tuple","components":[{"name":"label","type":"string"},{"name":"owner","type":"address"}]},{"name":"shares","type":"uint64[]"}]},
+	{"type":"function","name":"set","stateMutability":"payable",
+		"inputs":[{"name":"id","type":"uint256"},{"name":"entry","type":"tuple","components":[{"name":"label","type":"string"},{"name":"owner","type":"address"}]},{"name":"data","type":"bytes"}],
+		"outputs":[]},
+	{"type":"event","name":"Set","anonymous":false,
+		"inputs":[{"name":"id","type":"uint256","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"label","type":"string","indexed":false}]}
+]`
+
+// ledgerEntry is the Go form of the entry tuple of ledgerABI
+type ledgerEntry struct {
+	Label string
+	Owner common.Address
+}
+
+// A test function that tests resolving a contract and calling its methods through its ABI.
+func (ts *TableSuite) TestCallAndSend() {
+	ledger := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 22, Name: "Ledger", Address: ledger, ABI: []byte(ledgerABI)}))
+
+	parsed, err := abi.JSON(bytes.NewReader([]byte(ledgerABI)))
+	ts.Require().NoError(err)
+
+	newContract := func() (*contract.Contract, *contract.MockIBlockchain) {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(22), nil).AnyTimes()
+
+		return contract.NewContract(m, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner)), m
+	}
+
+	ts.Run("Resolves contracts by name and address", func() {
+		c, _ := newContract()
+
+		for _, ref := range []string{"Ledger", ledger.Hex(), address.ToBech32(ledger)} {
+			target, err := c.Resolve(ref, "")
+			ts.Require().NoError(err)
+			assert.Equal(ts.T(), "Ledger", target.Name)
+			assert.Equal(ts.T(), ledger, target.Address)
+			assert.Contains(ts.T(), target.ABI.Methods, "set")
+		}
+	})
+
+	ts.Run("Unknown addresses need an ABI file", func() {
+		c, _ := newContract()
+
+		_, err := c.Resolve(holderAddr.Hex(), "")
+		assert.ErrorIs(ts.T(), err, contract.ErrNoABI)
+
+		path := filepath.Join(ts.T().TempDir(), "Ledger.abi")
+		ts.Require().NoError(os.WriteFile(path, []byte(ledgerABI), 0o600))
+
+		target, err := c.Resolve(holderAddr.Hex(), path)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), holderAddr, target.Address)
+
+		_, err = c.Resolve("0x1234", "")
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidAddress)
+	})
+
+	target := &contract.Target{Name: "Ledger", Address: ledger, ABI: parsed}
+
+	ts.Run("Calls a method and decodes its return values", func() {
+		c, m := newContract()
+
+		packed, err := parsed.Methods["get"].Outputs.Pack(ledgerEntry{Label: "gold", Owner: holderAddr}, []uint64{3, 4})
+		ts.Require().NoError(err)
+
+		input, err := parsed.Pack("get", big.NewInt(7))
+		ts.Require().NoError(err)
+
+		m.EXPECT().CallContract(gomock.Any(), ethereum.CallMsg{From: testAddr, To: &ledger, Data: input}, nil).Return(packed, nil)
+
+		vals, err := c.Call(target, "get", "7")
+		ts.Require().NoError(err)
+		ts.Require().Len(vals, 2)
+
+		assert.Equal(ts.T(), "entry", vals[0].Name)
+		assert.Equal(ts.T(), `{"label":"gold","owner":"`+holderAddr.Hex()+`"}`, vals[0].String())
+		assert.Equal(ts.T(), "uint64[]", vals[1].Type)
+		assert.Equal(ts.T(), `["3","4"]`, vals[1].String())
+	})
+
+	callErrs := []struct {
+		name    string
+		method  string
+		args    []string
+		wantErr error
+	}{
+		{name: "Unknown method", method: "missing", wantErr: contract.ErrUnknownMethod},
+		{name: "Invalid argument", method: "get", args: []string{"seven"}, wantErr: contract.ErrInvalidArgument},
+		{name: "Missing argument", method: "get", wantErr: contract.ErrInvalidArgument},
+	}
+
+	for _, tt := range callErrs {
+		ts.Run(tt.name, func() {
+			c, _ := newContract()
+
+			_, err := c.Call(target, tt.method, tt.args...)
+			assert.ErrorIs(ts.T(), err, tt.wantErr)
+		})
+	}
+
+	ts.Run("Sends a transaction with tuple and bytes arguments", func() {
+		c, m := newContract()
+
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{}, nil)
+		m.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(1000), nil)
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).Return(uint64(5), nil)
+		m.EXPECT().PendingCodeAt(gomock.Any(), ledger).Return([]byte{0x60}, nil)
+		m.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(uint64(50000), nil)
+
+		var sent *types.Transaction
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			sent = tx
+			return nil
+		})
+
+		entry := `{"label":"gold","owner":"` + address.ToBech32(holderAddr) + `"}`
+
+		tx, err := c.Send(target, "set", big.NewInt(10), "7", entry, "0xcafe")
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), sent, tx)
+
+		want, err := parsed.Pack("set", big.NewInt(7), ledgerEntry{Label: "gold", Owner: holderAddr}, []byte{0xca, 0xfe})
+		ts.Require().NoError(err)
+
+		assert.Equal(ts.T(), want, tx.Data())
+		assert.Equal(ts.T(), big.NewInt(10), tx.Value())
+		assert.Equal(ts.T(), uint64(5), tx.Nonce())
+		assert.Equal(ts.T(), &ledger, tx.To())
+	})
+
+	ts.Run("Value is rejected for methods that are not payable", func() {
+		c, _ := newContract()
+
+		_, err := c.Send(target, "get", big.NewInt(1), "7")
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidArgument)
+	})
+
+	ts.Run("Decodes emitted events", func() {
+		ev := parsed.Events["Set"]
+
+		data, err := ev.Inputs.NonIndexed().Pack("gold")
+		ts.Require().NoError(err)
+
+		logs := []*types.Log{
+			{Address: spenderAddr, Topics: []common.Hash{common.HexToHash("0x01")}, Index: 0},
+			{Address: ledger, Topics: []common.Hash{ev.ID, common.BigToHash(big.NewInt(7)), common.BytesToHash(holderAddr.Bytes())}, Data: data, Index: 1},
+		}
+
+		events := target.DecodeEvents(logs)
+		ts.Require().Len(events, 1)
+		assert.Equal(ts.T(), "Set", events[0].Name)
+		assert.Equal(ts.T(), ledger, events[0].Address)
+		assert.Equal(ts.T(), uint(1), events[0].LogIndex)
+
+		var printed []string
+		for _, arg := range events[0].Args {
+			printed = append(printed, arg.Name+"="+arg.String())
+		}
+
+		assert.Equal(ts.T(), []string{"id=7", "owner=" + holderAddr.Hex(), "label=gold"}, printed)
+	})
+}
@@ -0,0 +1,401 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/manifest"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// Statuses of the steps reported by `Apply`
+const (
+	// StepApplied is a step whose transaction was sent, or resumed, and mined by this run
+	StepApplied = "applied"
+	// StepSkipped is a step applied by a previous run
+	StepSkipped = "skipped"
+	// StepChanged is a step applied by a previous run whose definition or bytecode changed since, it is not
+	// applied again, rename it to do so
+	StepChanged = "changed"
+	// StepExisting is a CREATE2 deployment whose address already held the contract
+	StepExisting = "existing"
+)
+
+// StepResult reports what `Apply` did with a step of the manifest.
+type StepResult struct {
+	ID     string      `json:"id"`
+	Status string      `json:"status"`
+	TxHash common.Hash `json:"txHash"`
+	// Address is the contract deployed by a deploy step
+	Address common.Address `json:"address,omitempty"`
+}
+
+// Apply deploys the contracts of the manifest and makes its calls in dependency order, each transaction is waited
+// for with the given options before the next step. The progress of every step is saved in the registry, so steps
+// applied by an earlier run are skipped and a run that failed or was interrupted resumes where it stopped: every
+// transaction is saved signed before it is sent, a step whose transaction was saved is waited for again instead of
+// being sent twice, the saved transaction is sent again as is when the node does not know it, and a new one is only
+// sent when it reverted or its nonce was taken. The results of the steps handled up to a failure are returned along with it.
+func (c *Contract) Apply(m *manifest.Manifest, wait WaitOpts) ([]StepResult, error) {
+	return c.ApplyContext(context.Background(), m, wait)
+}
+
+// ApplyContext is like `Apply` but every node call is bound to the given context.
+func (c *Contract) ApplyContext(ctx context.Context, m *manifest.Manifest, wait WaitOpts) ([]StepResult, error) {
+	if c.Registry == nil {
+		log.Err(ErrNoRegistry).Msg("unable to apply manifest")
+		return nil, ErrNoRegistry
+	}
+
+	plan, err := m.Plan()
+	if err != nil {
+		log.Err(err).Msg("unable to plan manifest")
+		return nil, err
+	}
+
+	chainID, err := c.sendingChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &applier{c: c, m: m, chainID: chainID.Uint64(), wait: wait, addresses: map[string]common.Address{}}
+
+	results := make([]StepResult, 0, len(plan))
+	for _, s := range plan {
+		res, err := a.apply(ctx, s)
+		if err != nil {
+			err = fmt.Errorf("step %s: %w", s.ID, err)
+			log.Err(err).Msgf("unable to apply %s", m.Name)
+			return results, err
+		}
+
+		if s.Deploy != nil {
+			a.addresses[s.ID] = res.Address
+		}
+
+		log.Info().Msgf("%s %s", res.ID, res.Status)
+		results = append(results, *res)
+	}
+
+	return results, nil
+}
+
+// applier holds the state of a single `Apply` run.
+type applier struct {
+	c       *Contract
+	m       *manifest.Manifest
+	chainID uint64
+	wait    WaitOpts
+	// addresses are the contracts deployed by the steps applied so far, by name
+	addresses map[string]common.Address
+}
+
+func (a *applier) apply(ctx context.Context, s *manifest.Step) (*StepResult, error) {
+	digest, err := a.m.Digest(s)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err := a.c.Registry.Step(a.chainID, a.m.Name, s.ID)
+
+	switch {
+	case errors.Is(err, registry.ErrNotFound):
+		step = &registry.Step{ChainID: a.chainID, Manifest: a.m.Name, ID: s.ID, Digest: digest}
+	case err != nil:
+		return nil, err
+	case step.Done:
+		status := StepSkipped
+		if step.Digest != digest {
+			status = StepChanged
+			log.Warn().Msgf("%s changed since it was applied, rename it to apply it again", s.ID)
+		}
+
+		return &StepResult{ID: s.ID, Status: status, TxHash: step.TxHash, Address: step.Address}, nil
+	default:
+		// sent by a run that failed or was interrupted before the transaction was mined
+		step.Digest = digest
+
+		receipt, err := a.resume(ctx, step)
+		if err != nil {
+			return nil, err
+		}
+
+		if receipt != nil {
+			return a.done(ctx, s, step, receipt)
+		}
+	}
+
+	// saved before it is sent so an interrupted run waits for this transaction instead of sending another one
+	save := func(tx *types.Transaction) error {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		step.TxHash, step.Raw, step.Address = tx.Hash(), raw, common.Address{}
+		if s.Deploy != nil {
+			step.Address = deployedAddress(a.c.Signer.Address(), tx)
+		}
+
+		return a.c.Registry.PutStep(step)
+	}
+
+	tx, address, err := a.send(ctx, s, save)
+	if errors.Is(err, ErrAlreadyDeployed) {
+		step.Address, step.Done = address, true
+		if err := a.c.Registry.PutStep(step); err != nil {
+			return nil, err
+		}
+
+		return &StepResult{ID: s.ID, Status: StepExisting, Address: address}, nil
+	}
+
+	if err != nil {
+		a.forget(ctx, step)
+		return nil, err
+	}
+
+	receipt, err := a.c.WaitMinedContext(ctx, tx.Hash(), a.wait)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.done(ctx, s, step, receipt)
+}
+
+// resume waits for the transaction of a step sent by an earlier run, a nil receipt means a new transaction has to
+// be sent: it reverted, or it was lost and its nonce was taken by another transaction.
+func (a *applier) resume(ctx context.Context, step *registry.Step) (*types.Receipt, error) {
+	txHash := step.TxHash
+	if txHash == (common.Hash{}) {
+		return nil, nil
+	}
+
+	_, _, err := a.c.Client.TransactionByHash(ctx, txHash)
+	switch {
+	case errors.Is(err, ethereum.NotFound) && len(step.Raw) == 0:
+		// saved by a version that did not keep the signed transaction
+		log.Warn().Msgf("transaction %s was dropped, sending it again", txHash.Hex())
+		return nil, nil
+	case errors.Is(err, ethereum.NotFound):
+		sent, err := a.rebroadcast(ctx, step)
+		if err != nil || !sent {
+			return nil, err
+		}
+	case err != nil:
+		log.Err(err).Msg("unable to get transaction")
+		return nil, err
+	}
+
+	receipt, err := a.c.WaitMinedContext(ctx, txHash, a.wait)
+	if errors.Is(err, ErrTxReverted) {
+		log.Warn().Msgf("transaction %s reverted, sending it again", txHash.Hex())
+		return nil, nil
+	}
+
+	return receipt, err
+}
+
+// rebroadcast sends the saved transaction of a step the node does not know as is, the run that saved it crashed
+// before sending it or it was dropped. Keeping its nonce it can not be mined along with a new transaction of the
+// step, it reports false when the nonce was taken by another transaction and a new one has to be sent.
+func (a *applier) rebroadcast(ctx context.Context, step *registry.Step) (bool, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(step.Raw); err != nil {
+		err = fmt.Errorf("%w: saved transaction of %s: %v", ErrInvalidTransaction, step.ID, err)
+		log.Err(err).Msg("unable to resume step")
+		return false, err
+	}
+
+	log.Warn().Msgf("transaction %s is unknown to the node, sending it again", step.TxHash.Hex())
+
+	err := a.c.Client.SendTransaction(ctx, tx)
+	if IsNonceError(err) {
+		// its nonce was used by another transaction, unless it was this one mined meanwhile
+		if _, _, err := a.c.Client.TransactionByHash(ctx, step.TxHash); errors.Is(err, ethereum.NotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		log.Err(err).Msg("unable to send transaction")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// forget drops the saved transaction of a step the node did not take when sending it, so the next run sends a new
+// one instead of waiting for it.
+func (a *applier) forget(ctx context.Context, step *registry.Step) {
+	if step.TxHash == (common.Hash{}) {
+		return
+	}
+
+	if _, _, err := a.c.Client.TransactionByHash(ctx, step.TxHash); !errors.Is(err, ethereum.NotFound) {
+		return
+	}
+
+	step.TxHash, step.Raw, step.Address = common.Hash{}, nil, common.Address{}
+	if err := a.c.Registry.PutStep(step); err != nil {
+		log.Err(err).Msg("unable to save step")
+	}
+}
+
+// done marks the step applied once its transaction is mined.
+func (a *applier) done(ctx context.Context, s *manifest.Step, step *registry.Step, receipt *types.Receipt) (*StepResult, error) {
+	if s.Deploy != nil && step.Address != (common.Address{}) {
+		if err := a.c.RecordMined(ctx, s.ID, receipt); err != nil {
+			log.Err(err).Msg("unable to update deployment block number in registry")
+		}
+	}
+
+	step.Done, step.Raw = true, nil
+	if err := a.c.Registry.PutStep(step); err != nil {
+		return nil, err
+	}
+
+	return &StepResult{ID: s.ID, Status: StepApplied, TxHash: step.TxHash, Address: step.Address}, nil
+}
+
+// send sends the transaction of the step once save kept it, the address is the contract deployed by deploy steps.
+func (a *applier) send(ctx context.Context, s *manifest.Step, save func(tx *types.Transaction) error) (*types.Transaction, common.Address, error) {
+	if s.Deploy != nil {
+		return a.deploy(ctx, s.Deploy, save)
+	}
+
+	call := s.Call
+
+	ref, err := a.expand(ctx, call.Contract)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	abiPath := call.ABI
+	if addr, ok := a.addresses[ref]; ok {
+		// contracts of the manifest are called at the address they were deployed to, with their own ABI
+		ref = addr.Hex()
+		if abiPath == "" {
+			abiPath = a.deployOf(call.Contract).ABI
+		}
+	}
+
+	target, err := a.c.ResolveContext(ctx, ref, a.m.Path(abiPath))
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	args, err := a.expandAll(ctx, call.Args)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	var value *big.Int
+	if call.Value != "" {
+		if value, err = ParseAmount(call.Value); err != nil {
+			return nil, common.Address{}, err
+		}
+	}
+
+	tx, err := a.c.send(ctx, target, call.Method, value, save, args...)
+
+	return tx, common.Address{}, err
+}
+
+func (a *applier) deploy(ctx context.Context, d *manifest.Deploy, save func(tx *types.Transaction) error) (*types.Transaction, common.Address, error) {
+	artifact := GoldcoinArtifact()
+	if d.ABI != "" {
+		var err error
+		if artifact, err = LoadArtifact(a.m.Path(d.ABI), a.m.Path(d.Bin)); err != nil {
+			return nil, common.Address{}, err
+		}
+	}
+
+	artifact.Name = d.Name
+
+	args, err := a.expandAll(ctx, d.Args)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	if d.Salt == "" {
+		address, tx, err := a.c.deployArtifact(ctx, artifact, save, args...)
+		return tx, address, err
+	}
+
+	s, err := a.expand(ctx, d.Salt)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	salt, err := ParseSalt(s)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	address, tx, err := a.c.deployCreate2(ctx, artifact, salt, save, args...)
+
+	return tx, address, err
+}
+
+// deployedAddress returns the contract created by a deploy transaction of the sender, a transaction sent to the
+// deterministic deployer carries the salt and init code the address is derived from.
+func deployedAddress(from common.Address, tx *types.Transaction) common.Address {
+	if tx.To() == nil {
+		return crypto.CreateAddress(from, tx.Nonce())
+	}
+
+	data := tx.Data()
+	if len(data) < common.HashLength {
+		return common.Address{}
+	}
+
+	return Create2Address(common.BytesToHash(data[:common.HashLength]), data[common.HashLength:])
+}
+
+// deployOf returns the deploy step of the named contract.
+func (a *applier) deployOf(name string) *manifest.Deploy {
+	for _, d := range a.m.Contracts {
+		if d.Name == name {
+			return d
+		}
+	}
+
+	return &manifest.Deploy{}
+}
+
+// expand replaces the `${Name}` references of s, contracts outside the manifest are resolved from the registry.
+func (a *applier) expand(ctx context.Context, s string) (string, error) {
+	return manifest.Expand(s, func(name string) (common.Address, error) {
+		if addr, ok := a.addresses[name]; ok {
+			return addr, nil
+		}
+
+		rec, err := a.c.latestDeployment(ctx, name)
+		if err != nil {
+			return common.Address{}, err
+		}
+
+		return rec.Address, nil
+	})
+}
+
+func (a *applier) expandAll(ctx context.Context, args []string) ([]string, error) {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		if expanded[i], err = a.expand(ctx, arg); err != nil {
+			return nil, err
+		}
+	}
+
+	return expanded, nil
+}
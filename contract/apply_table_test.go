@@ -0,0 +1,265 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/manifest"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// A test function that tests applying a deployment manifest and resuming it.
+func (ts *TableSuite) TestApply() {
+	m, err := manifest.Parse([]byte(`
+name: tokens
+contracts:
+  - name: Goldcoin
+calls:
+  - contract: Goldcoin
+    method: transfer
+    args: ["` + holderAddr.Hex() + `", 5]
+  - contract: Goldcoin
+    method: approve
+    args: ["${Goldcoin}", 7]
+`))
+	ts.Require().NoError(err)
+
+	token := crypto.CreateAddress(testAddr, 3)
+
+	parsed := contract.GoldcoinArtifact().ABI
+	transfer, err := parsed.Pack("transfer", holderAddr, big.NewInt(5))
+	ts.Require().NoError(err)
+	approve, err := parsed.Pack("approve", token, big.NewInt(7))
+	ts.Require().NoError(err)
+
+	// newMock expects the node calls of every step on the given chain, fail rejects the transactions it returns an error for
+	newMock := func(chainID int64, sent *[]*types.Transaction, fail func(tx *types.Transaction) error) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(chainID), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{}, nil).AnyTimes()
+		m.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(1000), nil).AnyTimes()
+		// the transactions sent are counted in the pending nonce, from the deployment of the token at nonce 3 on
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).DoAndReturn(func(context.Context, common.Address) (uint64, error) {
+			return uint64(3 + len(*sent)), nil
+		}).AnyTimes()
+		m.EXPECT().PendingCodeAt(gomock.Any(), token).Return([]byte{0x60}, nil).AnyTimes()
+		m.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(uint64(900000), nil).AnyTimes()
+		m.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			if fail != nil {
+				if err := fail(tx); err != nil {
+					return err
+				}
+			}
+
+			*sent = append(*sent, tx)
+			return nil
+		}).AnyTimes()
+		m.EXPECT().TransactionReceipt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+			return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(5)}, nil
+		}).AnyTimes()
+
+		return m
+	}
+
+	statuses := func(results []contract.StepResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.ID+" "+r.Status)
+		}
+
+		return out
+	}
+
+	ts.Run("Applies the steps in order and skips them afterwards", func() {
+		var sent []*types.Transaction
+		c := contract.NewContract(newMock(26, &sent, nil), contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err := c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{"Goldcoin applied", "Goldcoin.transfer applied", "Goldcoin.approve applied"}, statuses(results))
+		assert.Equal(ts.T(), token, results[0].Address)
+
+		ts.Require().Len(sent, 3)
+		assert.Nil(ts.T(), sent[0].To())
+		assert.Equal(ts.T(), common.FromHex(goldcoin.GoldcoinBin), sent[0].Data())
+		assert.Equal(ts.T(), transfer, sent[1].Data())
+		assert.Equal(ts.T(), approve, sent[2].Data(), "references are replaced by the deployed address")
+
+		rec, err := ts.Registry.Latest(26, contract.GoldcoinName)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), uint64(5), rec.BlockNumber)
+
+		results, err = c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{"Goldcoin skipped", "Goldcoin.transfer skipped", "Goldcoin.approve skipped"}, statuses(results))
+		assert.Len(ts.T(), sent, 3, "nothing is sent again")
+	})
+
+	ts.Run("Resumes after a failed step", func() {
+		var sent []*types.Transaction
+		rejected := errors.New("insufficient funds")
+		failTransfer := func(tx *types.Transaction) error {
+			if tx.To() != nil && string(tx.Data()) == string(transfer) {
+				return rejected
+			}
+
+			return nil
+		}
+
+		mock := newMock(28, &sent, failTransfer)
+		// the rejected transfer is unknown to the node and not waited for by the next run
+		mock.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).Return(nil, false, ethereum.NotFound)
+
+		c := contract.NewContract(mock, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err := c.Apply(m, contract.WaitOpts{})
+		assert.ErrorIs(ts.T(), err, rejected)
+		assert.Equal(ts.T(), []string{"Goldcoin applied"}, statuses(results))
+
+		c = contract.NewContract(newMock(28, &sent, nil), contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err = c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{"Goldcoin skipped", "Goldcoin.transfer applied", "Goldcoin.approve applied"}, statuses(results))
+		assert.Len(ts.T(), sent, 3)
+	})
+
+	ts.Run("Waits for a step sent by an interrupted run", func() {
+		pending := common.HexToHash("0xaa")
+		dropped := common.HexToHash("0xbb")
+
+		ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 30, Name: contract.GoldcoinName, Address: token, TxHash: pending, ABI: []byte(goldcoin.GoldcoinABI)}))
+		ts.Require().NoError(ts.Registry.PutStep(&registry.Step{ChainID: 30, Manifest: "tokens", ID: "Goldcoin", TxHash: pending, Address: token}))
+		ts.Require().NoError(ts.Registry.PutStep(&registry.Step{ChainID: 30, Manifest: "tokens", ID: "Goldcoin.transfer", TxHash: dropped}))
+
+		var sent []*types.Transaction
+		mock := newMock(30, &sent, nil)
+		mock.EXPECT().TransactionByHash(gomock.Any(), pending).Return(types.NewTx(&types.LegacyTx{}), false, nil)
+		mock.EXPECT().TransactionByHash(gomock.Any(), dropped).Return(nil, false, ethereum.NotFound)
+
+		c := contract.NewContract(mock, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err := c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), pending, results[0].TxHash, "the pending deployment is not sent again")
+
+		ts.Require().Len(sent, 2, "the dropped transfer is sent again")
+		assert.Equal(ts.T(), transfer, sent[0].Data())
+	})
+
+	ts.Run("Saves every transaction before sending it", func() {
+		saved := func(tx *types.Transaction) error {
+			raw, err := tx.MarshalBinary()
+			ts.Require().NoError(err)
+
+			for _, id := range []string{"Goldcoin", "Goldcoin.transfer", "Goldcoin.approve"} {
+				if step, err := ts.Registry.Step(42, "tokens", id); err == nil && step.TxHash == tx.Hash() {
+					assert.Equal(ts.T(), raw, []byte(step.Raw))
+					return nil
+				}
+			}
+
+			ts.Failf("transaction sent before it was saved", "%s", tx.Hash().Hex())
+
+			return nil
+		}
+
+		var sent []*types.Transaction
+		c := contract.NewContract(newMock(42, &sent, saved), contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		_, err := c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Len(ts.T(), sent, 3)
+
+		step, err := ts.Registry.Step(42, "tokens", "Goldcoin")
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), token, step.Address)
+		assert.Empty(ts.T(), step.Raw, "the transaction is not kept once mined")
+	})
+
+	// crashed saves the deployment and the transfer signed by a run that crashed before sending the transfer
+	crashed := func(chainID int64) *types.Transaction {
+		tx, err := testSigner.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce: 4, To: &token, Gas: 900000, GasPrice: big.NewInt(1000), Data: transfer,
+		}), big.NewInt(chainID))
+		ts.Require().NoError(err)
+
+		raw, err := tx.MarshalBinary()
+		ts.Require().NoError(err)
+
+		plan, err := m.Plan()
+		ts.Require().NoError(err)
+		digest, err := m.Digest(plan[0])
+		ts.Require().NoError(err)
+
+		deployed := common.HexToHash("0xcc")
+		ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: uint64(chainID), Name: contract.GoldcoinName, Address: token, TxHash: deployed, ABI: []byte(goldcoin.GoldcoinABI)}))
+		ts.Require().NoError(ts.Registry.PutStep(&registry.Step{ChainID: uint64(chainID), Manifest: "tokens", ID: "Goldcoin", Digest: digest, TxHash: deployed, Address: token, Done: true}))
+		ts.Require().NoError(ts.Registry.PutStep(&registry.Step{ChainID: uint64(chainID), Manifest: "tokens", ID: "Goldcoin.transfer", TxHash: tx.Hash(), Raw: raw}))
+
+		return tx
+	}
+
+	ts.Run("Sends the transaction saved by a crashed run as is", func() {
+		saved := crashed(43)
+
+		var sent []*types.Transaction
+		mock := newMock(43, &sent, nil)
+		mock.EXPECT().TransactionByHash(gomock.Any(), saved.Hash()).Return(nil, false, ethereum.NotFound)
+
+		c := contract.NewContract(mock, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err := c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{"Goldcoin skipped", "Goldcoin.transfer applied", "Goldcoin.approve applied"}, statuses(results))
+		assert.Equal(ts.T(), saved.Hash(), results[1].TxHash)
+
+		ts.Require().Len(sent, 2, "the transfer is not signed again")
+		assert.Equal(ts.T(), saved.Hash(), sent[0].Hash())
+	})
+
+	ts.Run("Sends a new transaction when the saved one lost its nonce", func() {
+		saved := crashed(44)
+		taken := func(tx *types.Transaction) error {
+			if tx.Hash() == saved.Hash() {
+				return errors.New("nonce too low")
+			}
+
+			return nil
+		}
+
+		var sent []*types.Transaction
+		mock := newMock(44, &sent, taken)
+		mock.EXPECT().TransactionByHash(gomock.Any(), saved.Hash()).Return(nil, false, ethereum.NotFound).Times(2)
+
+		c := contract.NewContract(mock, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		results, err := c.Apply(m, contract.WaitOpts{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{"Goldcoin skipped", "Goldcoin.transfer applied", "Goldcoin.approve applied"}, statuses(results))
+
+		ts.Require().Len(sent, 2)
+		assert.NotEqual(ts.T(), saved.Hash(), sent[0].Hash())
+		assert.Equal(ts.T(), transfer, sent[0].Data())
+	})
+
+	ts.Run("Refuses to send to another chain", func() {
+		var sent []*types.Transaction
+		c := contract.NewContract(newMock(32, &sent, nil), contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner), contract.WithChainID(9000))
+
+		_, err := c.Apply(m, contract.WaitOpts{})
+		assert.ErrorIs(ts.T(), err, contract.ErrChainMismatch)
+		assert.Empty(ts.T(), sent)
+	})
+}
@@ -0,0 +1,303 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/goldcoin"
+)
+
+// A variable that is assigned to the function `bind.DeployContract` : like `Deploy` it is public for monkeypatching in tests
+var DeployContract = bind.DeployContract
+
+// Artifact is a compiled contract, the ABI and creation bytecode as written by `solc --abi` and `solc --bin`.
+type Artifact struct {
+	// Name is the name deployments of the artifact are recorded under in the registry
+	Name string
+	ABI  abi.ABI
+	Bin  []byte
+	// abiJSON is kept to record the ABI along with deployments
+	abiJSON []byte
+}
+
+// LoadArtifact reads the ABI and bytecode files of a contract, the artifact is named after the ABI file, eg.
+// `abi/Goldcoin.abi` is named Goldcoin.
+func LoadArtifact(abiPath, binPath string) (*Artifact, error) {
+	abiJSON, err := os.ReadFile(abiPath)
+	if err != nil {
+		return nil, err
+	}
+
+	bin, err := os.ReadFile(binPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseArtifact(strings.TrimSuffix(filepath.Base(abiPath), filepath.Ext(abiPath)), abiJSON, bin)
+}
+
+// ParseArtifact parses a JSON ABI and hex encoded bytecode, with or without 0x prefix.
+func ParseArtifact(name string, abiJSON, bin []byte) (*Artifact, error) {
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("abi of %s: %w", name, err)
+	}
+
+	code, err := hexutil.Decode("0x" + strings.TrimPrefix(strings.TrimSpace(string(bin)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("bytecode of %s: %w", name, err)
+	}
+
+	if len(code) == 0 {
+		return nil, fmt.Errorf("bytecode of %s: empty, abstract contracts and interfaces can not be deployed", name)
+	}
+
+	return &Artifact{Name: name, ABI: parsed, Bin: code, abiJSON: abiJSON}, nil
+}
+
+// GoldcoinArtifact returns the bundled goldcoin contract as an artifact, to deploy it like any compiled contract.
+func GoldcoinArtifact() *Artifact {
+	a, err := ParseArtifact(GoldcoinName, []byte(goldcoin.GoldcoinABI), []byte(goldcoin.GoldcoinBin))
+	if err != nil {
+		// the binding is generated from a valid ABI and bytecode
+		panic(err)
+	}
+
+	return a
+}
+
+// DeployArtifact deploys the artifact with the constructor arguments given as strings, they are converted to the
+// types of the constructor inputs with `ParseArguments`. The deployment is recorded under the artifact name.
+func (c *Contract) DeployArtifact(a *Artifact, args ...string) (common.Address, *types.Transaction, error) {
+	return c.DeployArtifactContext(context.Background(), a, args...)
+}
+
+// DeployArtifactContext is like `DeployArtifact` but every node call made while deploying is bound to the given context.
+func (c *Contract) DeployArtifactContext(ctx context.Context, a *Artifact, args ...string) (common.Address, *types.Transaction, error) {
+	return c.deployArtifact(ctx, a, nil, args...)
+}
+
+// deployArtifact deploys the artifact, the deploy transaction is kept with save before it is sent when save is
+// given, see `sendSaved`.
+func (c *Contract) deployArtifact(ctx context.Context, a *Artifact, save func(tx *types.Transaction) error, args ...string) (common.Address, *types.Transaction, error) {
+	params, err := ParseArguments(a.ABI.Constructor.Inputs, args)
+	if err != nil {
+		log.Err(err).Msgf("invalid constructor arguments of %s", a.Name)
+		return common.Address{}, nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	defer release()
+
+	auth.NoSend = save != nil
+
+	address, tx, _, err := DeployContract(auth, a.ABI, a.Bin, c.backend(), params...)
+	if err != nil {
+		log.Err(err).Msgf("Unable to deploy %s", a.Name)
+		return common.Address{}, nil, err
+	}
+
+	if err := c.sendSaved(ctx, tx, save); err != nil {
+		return common.Address{}, nil, err
+	}
+
+	c.recordSubmitted(ctx, a.Name, string(a.abiJSON), hexutil.Encode(a.Bin), auth.From, address, tx, nil)
+
+	return address, tx, nil
+}
+
+// ParseArguments converts command line arguments to the Go values the ABI arguments are packed from. Numbers are
+// base 10 or 0x hex, addresses 0x hex or evmos bech32, bytes 0x hex, arrays JSON arrays, eg. `["1","2"]`, and
+// tuples JSON objects keyed by component name or JSON arrays of the components.
+func ParseArguments(inputs abi.Arguments, args []string) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("%w: %d arguments given, %d expected", ErrInvalidArgument, len(args), len(inputs))
+	}
+
+	values := make([]interface{}, 0, len(args))
+	for i, input := range inputs {
+		name := input.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+
+		v, err := parseArgument(input.Type, args[i])
+		if err != nil {
+			return nil, &ArgumentError{Name: name, Type: input.Type.String(), Input: args[i], Err: err}
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// parseArgument converts a single argument to the Go type go-ethereum packs the ABI type from.
+func parseArgument(t abi.Type, s string) (interface{}, error) {
+	switch t.T {
+	case abi.StringTy:
+		return s, nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.AddressTy:
+		return ParseAddress(s)
+	case abi.IntTy, abi.UintTy:
+		return parseInteger(t, s)
+	case abi.BytesTy:
+		return hexutil.Decode(s)
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) > t.Size {
+			return nil, fmt.Errorf("%d bytes do not fit in bytes%d", len(b), t.Size)
+		}
+
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+
+		return v.Interface(), nil
+	case abi.SliceTy, abi.ArrayTy:
+		return parseList(t, s)
+	case abi.TupleTy:
+		return parseTuple(t, s)
+	default:
+		return nil, fmt.Errorf("type %s is not supported", t.String())
+	}
+}
+
+// parseInteger parses a base 10 or 0x hex integer in the range of the type, sizes up to 64 bits are packed from
+// the matching Go integer and larger ones from `*big.Int`.
+func parseInteger(t abi.Type, s string) (interface{}, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, errors.New("not an integer")
+	}
+
+	if t.T == abi.UintTy && n.Sign() < 0 {
+		return nil, errors.New("negative value for unsigned integer")
+	}
+
+	// signed integers keep a bit for the sign, -2^(size-1) itself is accepted
+	bits, magnitude := t.Size, n
+	if t.T == abi.IntTy {
+		bits--
+		if n.Sign() < 0 {
+			magnitude = new(big.Int).Add(n, big.NewInt(1))
+		}
+	}
+
+	if magnitude.BitLen() > bits {
+		return nil, fmt.Errorf("out of range for %s", t.String())
+	}
+
+	kind := t.GetType()
+	if kind == reflect.TypeOf(n) {
+		return n, nil
+	}
+
+	if t.T == abi.UintTy {
+		return reflect.ValueOf(n.Uint64()).Convert(kind).Interface(), nil
+	}
+
+	return reflect.ValueOf(n.Int64()).Convert(kind).Interface(), nil
+}
+
+// jsonElement returns a JSON string element unquoted and any other element, like numbers, booleans, arrays and
+// objects, as is.
+func jsonElement(r json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(r, &s); err != nil {
+		return string(r)
+	}
+
+	return s
+}
+
+// parseTuple parses a JSON object keyed by the component names or a JSON array of the components in order.
+func parseTuple(t abi.Type, s string) (interface{}, error) {
+	raw := make([]json.RawMessage, len(t.TupleElems))
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &fields); err == nil {
+		for i, name := range t.TupleRawNames {
+			r, ok := fields[name]
+			if !ok {
+				return nil, fmt.Errorf("component %s missing", name)
+			}
+
+			raw[i] = r
+		}
+
+		if len(fields) != len(raw) {
+			return nil, fmt.Errorf("%d components given, %d expected", len(fields), len(raw))
+		}
+	} else if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, errors.New("not a JSON object or array")
+	} else if len(raw) != len(t.TupleElems) {
+		return nil, fmt.Errorf("%d components given, %d expected", len(raw), len(t.TupleElems))
+	}
+
+	v := reflect.New(t.GetType()).Elem()
+	for i, elem := range t.TupleElems {
+		e, err := parseArgument(*elem, jsonElement(raw[i]))
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", t.TupleRawNames[i], err)
+		}
+
+		v.Field(i).Set(reflect.ValueOf(e))
+	}
+
+	return v.Interface(), nil
+}
+
+// parseList parses a JSON array, elements may be JSON strings or, for numbers and booleans, bare JSON values.
+func parseList(t abi.Type, s string) (interface{}, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("not a JSON array: %w", err)
+	}
+
+	if t.T == abi.ArrayTy && len(raw) != t.Size {
+		return nil, fmt.Errorf("%d elements given, %d expected", len(raw), t.Size)
+	}
+
+	var v reflect.Value
+	if t.T == abi.ArrayTy {
+		v = reflect.New(t.GetType()).Elem()
+	} else {
+		v = reflect.MakeSlice(t.GetType(), len(raw), len(raw))
+	}
+
+	for i, r := range raw {
+		e, err := parseArgument(*t.Elem, jsonElement(r))
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		v.Index(i).Set(reflect.ValueOf(e))
+	}
+
+	return v.Interface(), nil
+}
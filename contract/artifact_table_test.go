@@ -0,0 +1,134 @@
+package contract_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/address"
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// vaultABI has a constructor taking one argument of most ABI types
+const vaultABI = `[{"type":"constructor","stateMutability":"nonpayable","inputs":[
+	{"name":"label","type":"string"},
+	{"name":"owner","type":"address"},
+	{"name":"decimals","type":"uint8"},
+	{"name":"offset","type":"int256"},
+	{"name":"cap","type":"uint256"},
+	{"name":"active","type":"bool"},
+	{"name":"salt","type":"bytes32"},
+	{"name":"shares","type":"uint64[]"},
+	{"name":"pair","type":"address[2]"}
+]}]`
+
+// A test function that tests parsing constructor arguments and deploying an artifact.
+func (ts *TableSuite) TestDeployArtifact() {
+	dir := ts.T().TempDir()
+	ts.Require().NoError(os.WriteFile(filepath.Join(dir, "Vault.abi"), []byte(vaultABI), 0o600))
+	ts.Require().NoError(os.WriteFile(filepath.Join(dir, "Vault.bin"), []byte("6080604052\n"), 0o600))
+
+	artifact, err := contract.LoadArtifact(filepath.Join(dir, "Vault.abi"), filepath.Join(dir, "Vault.bin"))
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), "Vault", artifact.Name)
+	assert.Equal(ts.T(), []byte{0x60, 0x80, 0x60, 0x40, 0x52}, artifact.Bin)
+
+	bech32 := address.ToBech32(holderAddr)
+
+	args := []string{"vault", bech32, "18", "-5", "0x10", "true", "0x01", `[1,"2"]`, `["` + testAddr.Hex() + `","` + spenderAddr.Hex() + `"]`}
+
+	ts.Run("Converts arguments to their ABI types", func() {
+		values, err := contract.ParseArguments(artifact.ABI.Constructor.Inputs, args)
+		ts.Require().NoError(err)
+
+		var salt [32]byte
+		salt[0] = 1
+
+		assert.Equal(ts.T(), []interface{}{
+			"vault", holderAddr, uint8(18), big.NewInt(-5), big.NewInt(16), true, salt,
+			[]uint64{1, 2}, [2]common.Address{testAddr, spenderAddr},
+		}, values)
+
+		// the values are accepted by the packer
+		_, err = artifact.ABI.Pack("", values...)
+		assert.NoError(ts.T(), err)
+	})
+
+	subtests := []struct {
+		name    string
+		index   int
+		arg     string
+		wantErr error
+	}{
+		{name: "Uint out of range", index: 2, arg: "256", wantErr: contract.ErrInvalidArgument},
+		{name: "Negative uint", index: 4, arg: "-1", wantErr: contract.ErrInvalidArgument},
+		{name: "Not a number", index: 3, arg: "ten", wantErr: contract.ErrInvalidArgument},
+		{name: "Invalid address", index: 1, arg: "0x1234", wantErr: contract.ErrInvalidAddress},
+		{name: "Invalid bool", index: 5, arg: "maybe", wantErr: contract.ErrInvalidArgument},
+		{name: "Too many bytes", index: 6, arg: "0x" + common.Bytes2Hex(make([]byte, 33)), wantErr: contract.ErrInvalidArgument},
+		{name: "Array size mismatch", index: 8, arg: `["` + testAddr.Hex() + `"]`, wantErr: contract.ErrInvalidArgument},
+		{name: "Invalid slice element", index: 7, arg: `[1,-2]`, wantErr: contract.ErrInvalidArgument},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			bad := append([]string(nil), args...)
+			bad[tt.index] = tt.arg
+
+			_, err := contract.ParseArguments(artifact.ABI.Constructor.Inputs, bad)
+			assert.ErrorIs(ts.T(), err, tt.wantErr)
+
+			var argErr *contract.ArgumentError
+			assert.ErrorAs(ts.T(), err, &argErr)
+		})
+	}
+
+	ts.Run("Wrong number of arguments", func() {
+		_, err := contract.ParseArguments(artifact.ABI.Constructor.Inputs, args[:2])
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidArgument)
+	})
+
+	ts.Run("Deploys and records the artifact", func() {
+		defer func(deploy func(*bind.TransactOpts, abi.ABI, []byte, bind.ContractBackend, ...interface{}) (common.Address, *types.Transaction, *bind.BoundContract, error)) {
+			contract.DeployContract = deploy
+		}(contract.DeployContract)
+
+		deployed := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+		tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+
+		var params []interface{}
+		contract.DeployContract = func(opts *bind.TransactOpts, _ abi.ABI, bin []byte, _ bind.ContractBackend, p ...interface{}) (common.Address, *types.Transaction, *bind.BoundContract, error) {
+			assert.Equal(ts.T(), testAddr, opts.From)
+			assert.Equal(ts.T(), artifact.Bin, bin)
+			params = p
+
+			return deployed, tx, nil, nil
+		}
+
+		expectTxOpts(ts.ClientMock)
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(1), nil)
+
+		addr, sent, err := ts.Contract.DeployArtifact(artifact, args...)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), deployed, addr)
+		assert.Equal(ts.T(), tx, sent)
+		assert.Len(ts.T(), params, 9)
+
+		rec, err := ts.Registry.Latest(1, "Vault")
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), deployed, rec.Address)
+		assert.Equal(ts.T(), tx.Hash(), rec.TxHash)
+	})
+
+	ts.Run("Invalid arguments are rejected before signing", func() {
+		_, _, err := ts.Contract.DeployArtifact(artifact, "vault")
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidArgument)
+	})
+}
@@ -2,9 +2,11 @@ package contract
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
-	"os"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -14,10 +16,63 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/index"
+	"github.com/gopherine/evmos-conploy/registry"
 )
 
+// GoldcoinName is the name under which goldcoin deployments are kept in the registry
+const GoldcoinName = "Goldcoin"
+
+// ErrNoRegistry is returned when a lookup needs the deployment registry but none was configured
+var ErrNoRegistry = errors.New("no deployment registry configured")
+
+// ErrNoSigner is returned when a transaction or the owner address is needed but no signer was configured
+var ErrNoSigner = errors.New("no signer configured")
+
+// ErrChainMismatch is returned when the node is connected to another chain than the one the network expects
+var ErrChainMismatch = errors.New("chain id mismatch")
+
 type Contract struct {
-	Client IBlockchain
+	Client    IBlockchain
+	Signer    Signer
+	Registry  *registry.Registry
+	Index     *index.Index
+	GasPolicy GasPolicy
+	// ChainID is the chain the node must be connected to before anything is sent, any chain when zero
+	ChainID uint64
+	// Nonces allocates the nonces of the transactions sent, the pending nonce of the node is used when nil
+	Nonces *NonceManager
+}
+
+// Option configures optional dependencies of the `Contract`
+type Option func(*Contract)
+
+// WithSigner sets the signer transactions are sent from and signed with
+func WithSigner(s Signer) Option {
+	return func(c *Contract) {
+		c.Signer = s
+	}
+}
+
+// WithRegistry sets the registry deployments are recorded to and resolved from
+func WithRegistry(r *registry.Registry) Option {
+	return func(c *Contract) {
+		c.Registry = r
+	}
+}
+
+// WithIndex sets the local index token events are synced to and balances and allowances are served from
+func WithIndex(ix *index.Index) Option {
+	return func(c *Contract) {
+		c.Index = ix
+	}
+}
+
+// WithChainID sets the chain id the node is checked against before a transaction is sent
+func WithChainID(id uint64) Option {
+	return func(c *Contract) {
+		c.ChainID = id
+	}
 }
 
 // Below interface is directly refereced from https://github.com/bonedaddy/go-defi/blob/main/utils/blockchain.go
@@ -31,6 +86,8 @@ type IBlockchain interface {
 	// CodeAt returns the code of the given account. This is needed to differentiate
 	// between contract internal errors and the local chain being out of sync.
 	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	// BalanceAt returns the wei balance of the given account.
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
 	// ContractCall executes an Ethereum contract call with the specified data as the
 	// input.
 	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
@@ -77,18 +134,59 @@ type IBlockchain interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 }
 
+// Signer is the account owning the deployed contracts, it signs every transaction sent by the `Contract`.
+// Implementations for raw hex keys, keystore files and mnemonics are provided by the signer package.
+type Signer interface {
+	// Address returns the address of the account the signer signs for.
+	Address() common.Address
+	// SignTx signs the transaction for the given chain id.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
 // Defining the interface for the goldcoin contract.
 type IGoldcoin interface {
 	Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error)
 	// 	BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
 	// Solidity: function balanceOf(address account) view returns(uint256)
 	BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+	// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+	// Solidity: function decimals() view returns(uint8)
+	Decimals(opts *bind.CallOpts) (uint8, error)
+	// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+	// Solidity: function symbol() view returns(string)
+	Symbol(opts *bind.CallOpts) (string, error)
+	// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+	// Solidity: function approve(address spender, uint256 amount) returns(bool)
+	Approve(opts *bind.TransactOpts, spender common.Address, amount *big.Int) (*types.Transaction, error)
+	// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+	// Solidity: function allowance(address owner, address spender) view returns(uint256)
+	Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error)
+	// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+	// Solidity: function transferFrom(address from, address to, uint256 amount) returns(bool)
+	TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, amount *big.Int) (*types.Transaction, error)
+	// IncreaseAllowance is a paid mutator transaction binding the contract method 0x39509351.
+	// Solidity: function increaseAllowance(address spender, uint256 addedValue) returns(bool)
+	IncreaseAllowance(opts *bind.TransactOpts, spender common.Address, addedValue *big.Int) (*types.Transaction, error)
+	// DecreaseAllowance is a paid mutator transaction binding the contract method 0xa457c2d7.
+	// Solidity: function decreaseAllowance(address spender, uint256 subtractedValue) returns(bool)
+	DecreaseAllowance(opts *bind.TransactOpts, spender common.Address, subtractedValue *big.Int) (*types.Transaction, error)
+	// Name is a free data retrieval call binding the contract method 0x06fdde03.
+	// Solidity: function name() view returns(string)
+	Name(opts *bind.CallOpts) (string, error)
+	// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+	// Solidity: function totalSupply() view returns(uint256)
+	TotalSupply(opts *bind.CallOpts) (*big.Int, error)
 }
 
 // > The function `NewContract` takes an interface `IBlockchain` as an argument and returns a pointer
 // to a `Contract` struct
-func NewContract(c IBlockchain) *Contract {
-	return &Contract{c}
+func NewContract(c IBlockchain, opts ...Option) *Contract {
+	contract := &Contract{Client: c, Nonces: NewNonceManager(c)}
+	for _, opt := range opts {
+		opt(contract)
+	}
+
+	return contract
 }
 
 // A variable that is assigned to the function `goldcoin.DeployGoldcoin` : this is for testing purpose, for monkeypatching this variable needs to be public
@@ -96,78 +194,125 @@ var Deploy = goldcoin.DeployGoldcoin
 
 // Deploying the contract to the blockchain.
 func (c *Contract) Deploy() (instance *goldcoin.Goldcoin, addrHash string, txHash string, err error) {
-	auth, err := c.getTxSigner()
+	return c.DeployContext(context.Background())
+}
+
+// DeployContext is like `Deploy` but every node call made while deploying is bound to the given context.
+func (c *Contract) DeployContext(ctx context.Context) (instance *goldcoin.Goldcoin, addrHash string, txHash string, err error) {
+	auth, release, err := c.getTxSigner(ctx)
 	if err != nil {
 		return nil, "", "", err
 	}
+	defer release()
 
-	address, tx, instance, err := Deploy(auth, c.Client)
+	address, tx, instance, err := Deploy(auth, c.backend())
 	if err != nil {
 		log.Err(err).Msg("Unable to deploy contract")
 		return nil, "", "", err
 	}
 
+	c.recordSubmitted(ctx, GoldcoinName, goldcoin.GoldcoinABI, goldcoin.GoldcoinBin, auth.From, address, tx, nil)
+
 	// TODO: this return is here only for testing purpose or if it needs to be used globally somehow, eventually needs to be removed or refactored
 	return instance, address.Hex(), tx.Hash().Hex(), nil
 }
 
-// This function is loading the contract from the blockchain.
-func (c *Contract) Load() *goldcoin.Goldcoin {
-	address := common.HexToAddress((os.Getenv("CONTRACT_ADDRESS")))
-	instance, _ := goldcoin.NewGoldcoin(address, c.Client)
-	log.Info().Msg("contract is loaded")
-	return instance
+// This function is loading the latest deployed goldcoin contract recorded in the registry.
+func (c *Contract) Load() (*goldcoin.Goldcoin, error) {
+	return c.LoadContext(context.Background())
+}
+
+// LoadContext is like `Load` but resolves the contract with the given context.
+func (c *Contract) LoadContext(ctx context.Context) (*goldcoin.Goldcoin, error) {
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := goldcoin.NewGoldcoin(rec.Address, c.backend())
+	if err != nil {
+		log.Err(err).Msg("unable to load contract")
+		return nil, err
+	}
+
+	log.Info().Msgf("contract is loaded from %s", rec.Address.Hex())
+
+	return instance, nil
 }
 
-// This function is reading the contract data from the blockchain.
+// This function is reading the deployment reciept of the latest goldcoin contract recorded in the registry,
+// once mined the block number is saved back to the registry record.
 func (c *Contract) Reciept() (*types.Receipt, error) {
-	txHashHex := os.Getenv("CONTRACT_HASH")
-	txHash := common.HexToHash(txHashHex)
+	return c.RecieptContext(context.Background())
+}
+
+// RecieptContext is like `Reciept` but every node call is bound to the given context.
+func (c *Contract) RecieptContext(ctx context.Context) (*types.Receipt, error) {
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return nil, err
+	}
 
-	reciept, err := c.Client.TransactionReceipt(context.Background(), txHash)
+	reciept, err := c.Client.TransactionReceipt(ctx, rec.TxHash)
 	if err != nil {
 		log.Err(err).Msg("reciept not recieved, contract was not deployed")
 		return nil, err
 	}
 
+	if err := c.markMined(rec, reciept); err != nil {
+		log.Err(err).Msg("unable to update deployment block number in registry")
+	}
+
 	return reciept, nil
 }
 
-// Transfer tokens from owner address to reciever address
+// Transfer tokens from owner address to reciever address, given either as 0x hex or evmos bech32
 func (c *Contract) TransferTokens(instance IGoldcoin, recieverAddr string, amountStr string) (*types.Transaction, error) {
-	auth, err := c.getTxSigner()
+	return c.TransferTokensContext(context.Background(), instance, recieverAddr, amountStr)
+}
+
+// TransferTokensContext is like `TransferTokens` but the transaction is built and sent with the given context.
+func (c *Contract) TransferTokensContext(ctx context.Context, instance IGoldcoin, recieverAddr string, amountStr string) (*types.Transaction, error) {
+	to, amount, err := parseSpend(recieverAddr, amountStr)
 	if err != nil {
 		return nil, err
 	}
 
-	if amount, ok := new(big.Int).SetString(amountStr, 10); ok {
-		// This is a function that is defined in the contract to transfer tokens from owner address to
-		// reciever address.
-		tx, err := instance.Transfer(auth, common.HexToAddress(recieverAddr), amount)
-		if err != nil {
-			log.Err(err).Msg("unable to make transaction")
-			return nil, err
-		}
-		return tx, nil
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := checkBalance(ctx, instance, auth.From, amount); err != nil {
+		return nil, err
+	}
+
+	// This is a function that is defined in the contract to transfer tokens from owner address to
+	// reciever address.
+	tx, err := instance.Transfer(auth, to, amount)
+	if err != nil {
+		log.Err(err).Msg("unable to make transaction")
+		return nil, err
 	}
-	return nil, errors.New("Please check amount, something went wrong")
+
+	return tx, nil
 }
 
-// Checkbal function accepts hex string and returns balance in wei
-func (c *Contract) CheckBal(instance IGoldcoin, address string) (*big.Int, error) {
-	addr := common.HexToAddress(address)
-	if address == "" {
-		privateKey, err := crypto.HexToECDSA(os.Getenv("OWNER_PRIVATEKEY"))
-		if err != nil {
-			log.Err(err).Msg("Unable to process privatekey from HEX to ECDSA")
-			return nil, err
-		}
+// Checkbal function accepts hex or evmos bech32 address and returns balance in wei
+func (c *Contract) CheckBal(instance IGoldcoin, account string) (*big.Int, error) {
+	return c.CheckBalContext(context.Background(), instance, account)
+}
 
-		addr = crypto.PubkeyToAddress(privateKey.PublicKey)
+// CheckBalContext is like `CheckBal` but the balance is read with the given context.
+func (c *Contract) CheckBalContext(ctx context.Context, instance IGoldcoin, account string) (*big.Int, error) {
+	addr, err := c.accountOrOwner(account)
+	if err != nil {
+		return nil, err
 	}
 
 	// This is a function that is defined in the contract to get balance for given address
-	bal, err := instance.BalanceOf(&bind.CallOpts{}, addr)
+	bal, err := instance.BalanceOf(&bind.CallOpts{Context: ctx}, addr)
 	if err != nil {
 		log.Err(err).Msg("unable to get owner balance")
 		return nil, err
@@ -176,54 +321,255 @@ func (c *Contract) CheckBal(instance IGoldcoin, address string) (*big.Int, error
 	return bal, nil
 }
 
-// This function is creating a transaction signer.
-func (c *Contract) getTxSigner() (*bind.TransactOpts, error) {
-	chainId, err := c.Client.ChainID(context.Background())
+// accountOrOwner parses the account address, the address of the signer is used when it is empty.
+func (c *Contract) accountOrOwner(account string) (common.Address, error) {
+	if account == "" {
+		if c.Signer == nil {
+			log.Err(ErrNoSigner).Msg("unable to resolve owner address")
+			return common.Address{}, ErrNoSigner
+		}
+
+		return c.Signer.Address(), nil
+	}
+
+	addr, err := ParseAddress(account)
 	if err != nil {
-		log.Err(err).Msg("unable to get chain_id for evmos")
-		return nil, err
+		log.Err(err).Msg("invalid address")
+		return common.Address{}, err
 	}
 
-	// Convert privatekey Hex to ECDSA to create transaction signer
-	privateKey, err := crypto.HexToECDSA(os.Getenv("OWNER_PRIVATEKEY"))
+	return addr, nil
+}
+
+// parseRecipient parses the address tokens are sent or approved to, which must not be the zero address.
+func parseRecipient(s string) (common.Address, error) {
+	addr, err := ParseAddress(s)
 	if err != nil {
-		log.Err(err).Msg("Unable to generate private key")
-		return nil, err
+		return common.Address{}, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if addr == (common.Address{}) {
+		return common.Address{}, &AddressError{Input: s, Err: ErrZeroAddress}
+	}
+
+	return addr, nil
+}
+
+// checkBalance fails with `*InsufficientBalanceError` when the account holds less than amount. It is checked up
+// front as a transfer above the balance would only fail with an opaque gas estimation error.
+func checkBalance(ctx context.Context, instance IGoldcoin, account common.Address, amount *big.Int) error {
+	bal, err := instance.BalanceOf(&bind.CallOpts{Context: ctx}, account)
 	if err != nil {
-		log.Err(err).Msg("unable to create transaction signer")
-		return nil, err
+		log.Err(err).Msg("unable to get balance")
+		return err
+	}
+
+	if bal.Cmp(amount) < 0 {
+		err := &InsufficientBalanceError{Account: account, Balance: bal, Amount: amount}
+		log.Err(err).Msg("unable to make transaction")
+		return err
+	}
+
+	return nil
+}
+
+// This function is creating a transaction signer, the context is set on the returned options so bound
+// contracts make their own node calls with it as well. The nonce is reserved with the nonce manager until the
+// transaction is sent, release gives it back when it is not and must be deferred by the caller.
+func (c *Contract) getTxSigner(ctx context.Context) (auth *bind.TransactOpts, release func(), err error) {
+	if c.Signer == nil {
+		log.Err(ErrNoSigner).Msg("unable to create transaction signer")
+		return nil, nil, ErrNoSigner
 	}
 
-	gasPrice, err := c.Client.SuggestGasPrice(context.Background())
+	auth, err = c.txOpts(ctx, c.Signer.Address(), c.Signer.SignTx)
 	if err != nil {
-		log.Err(err).Msg("unable to get suggested gas price")
-		return nil, err
+		return nil, nil, err
+	}
+
+	if c.Nonces == nil {
+		nonce, err := c.Client.PendingNonceAt(ctx, auth.From)
+		if err != nil {
+			log.Err(err).Msg("unable to get nonce")
+			return nil, nil, err
+		}
+
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+
+		return auth, func() {}, nil
 	}
 
-	nonce, err := c.Client.PendingNonceAt(context.Background(), auth.From)
+	nonce, release, err := c.Nonces.Next(ctx, auth.From)
 	if err != nil {
-		log.Err(err).Msg("unable to get nonce")
-		return nil, err
+		return nil, nil, err
 	}
 
-	gasLimit, err := c.Client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From: auth.From,
-		To:   nil,
-		Data: common.FromHex(goldcoin.GoldcoinMetaData.Bin),
-	})
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+
+	return auth, release, nil
+}
+
+// txOpts assembles the options of a transaction sent from the given address: fees and nonce are fetched from
+// the node and sign is called with the chain id of the node once the transaction is built.
+func (c *Contract) txOpts(ctx context.Context, from common.Address, sign func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)) (*bind.TransactOpts, error) {
+	chainId, err := c.sendingChainID(ctx)
 	if err != nil {
-		log.Err(err).Msg("Unable to estimate gas limit")
+		return nil, err
+	}
+
+	auth := &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+
+			return sign(tx, chainId)
+		},
+	}
+
+	if err := c.setFees(ctx, auth); err != nil {
 		return nil, err
 	}
 
 	// Setting the transaction parameters.
-	auth.Nonce = big.NewInt(int64(nonce))
+	// Gas limit is left unset, bound contracts estimate it for the packed method call through `c.backend()`
 	auth.Value = big.NewInt(0) // in wei
-	auth.GasLimit = gasLimit   // in units
-	auth.GasPrice = gasPrice
+	auth.Context = ctx
 
 	return auth, nil
 }
+
+// sendingChainID returns the chain id of the node, failing with `ErrChainMismatch` when it is not the chain
+// transactions are meant for.
+func (c *Contract) sendingChainID(ctx context.Context) (*big.Int, error) {
+	chainID, err := c.Client.ChainID(ctx)
+	if err != nil {
+		log.Err(err).Msg("unable to get chain_id for evmos")
+		return nil, err
+	}
+
+	if chainID == nil {
+		log.Err(bind.ErrNoChainID).Msg("unable to create transaction signer")
+		return nil, bind.ErrNoChainID
+	}
+
+	if c.ChainID != 0 && chainID.Uint64() != c.ChainID {
+		err := fmt.Errorf("%w: node is on chain %d, expected %d", ErrChainMismatch, chainID, c.ChainID)
+		log.Err(err).Msg("refusing to send transaction")
+		return nil, err
+	}
+
+	return chainID, nil
+}
+
+// Deployments lists every deployment recorded in the registry for the connected chain.
+func (c *Contract) Deployments() ([]*registry.Record, error) {
+	return c.DeploymentsContext(context.Background())
+}
+
+// DeploymentsContext is like `Deployments` but resolves the chain with the given context.
+func (c *Contract) DeploymentsContext(ctx context.Context) ([]*registry.Record, error) {
+	if c.Registry == nil {
+		return nil, ErrNoRegistry
+	}
+
+	chainID, err := c.Client.ChainID(ctx)
+	if err != nil {
+		log.Err(err).Msg("unable to get chain_id for evmos")
+		return nil, err
+	}
+
+	return c.Registry.List(chainID.Uint64())
+}
+
+// sendSaved sends a transaction built with `NoSend` once save kept it, so a run interrupted after sending it finds
+// it again instead of sending another one. Nothing is done without save, the transaction was built and sent at once.
+func (c *Contract) sendSaved(ctx context.Context, tx *types.Transaction, save func(tx *types.Transaction) error) error {
+	if save == nil {
+		return nil
+	}
+
+	if err := save(tx); err != nil {
+		log.Err(err).Msg("unable to save transaction")
+		return err
+	}
+
+	if err := c.backend().SendTransaction(ctx, tx); err != nil {
+		log.Err(err).Msg("unable to send transaction")
+		return err
+	}
+
+	return nil
+}
+
+// recordSubmitted saves a freshly submitted deployment to the registry, it is a no-op when no registry is
+// configured. The salt is only given for CREATE2 deployments. The transaction is already submitted when it is
+// called, so failing to record it is logged and not returned, it must not hide the deployment from the caller.
+func (c *Contract) recordSubmitted(ctx context.Context, name, abiJSON, bin string, deployer, address common.Address, tx *types.Transaction, salt *common.Hash) {
+	if c.Registry == nil {
+		return
+	}
+
+	chainID, err := c.Client.ChainID(ctx)
+	if err == nil {
+		err = c.Registry.Put(&registry.Record{
+			ChainID:      chainID.Uint64(),
+			Name:         name,
+			Address:      address,
+			TxHash:       tx.Hash(),
+			Deployer:     deployer,
+			BytecodeHash: crypto.Keccak256Hash(common.FromHex(bin)),
+			Timestamp:    time.Now().UTC(),
+			ABI:          json.RawMessage(abiJSON),
+			Salt:         salt,
+		})
+	}
+
+	if err != nil {
+		log.Err(err).Msg("unable to record deployment in registry")
+	}
+}
+
+// RecordMined saves the block number of a mined deployment to its registry record, the record is matched
+// by the reciept's tx hash against the latest deployment of the named contract.
+func (c *Contract) RecordMined(ctx context.Context, name string, reciept *types.Receipt) error {
+	rec, err := c.latestDeployment(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return c.markMined(rec, reciept)
+}
+
+// markMined saves the reciept's block number to the record when it belongs to it and was not saved yet.
+func (c *Contract) markMined(rec *registry.Record, reciept *types.Receipt) error {
+	if rec.TxHash != reciept.TxHash || rec.BlockNumber != 0 || reciept.BlockNumber == nil {
+		return nil
+	}
+
+	rec.BlockNumber = reciept.BlockNumber.Uint64()
+
+	return c.Registry.Put(rec)
+}
+
+// latestDeployment resolves the most recent deployment of the named contract on the connected chain.
+func (c *Contract) latestDeployment(ctx context.Context, name string) (*registry.Record, error) {
+	if c.Registry == nil {
+		return nil, ErrNoRegistry
+	}
+
+	chainID, err := c.Client.ChainID(ctx)
+	if err != nil {
+		log.Err(err).Msg("unable to get chain_id for evmos")
+		return nil, err
+	}
+
+	rec, err := c.Registry.Latest(chainID.Uint64(), name)
+	if err != nil {
+		log.Err(err).Msg("unable to resolve contract from registry")
+		return nil, err
+	}
+
+	return rec, nil
+}
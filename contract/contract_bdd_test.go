@@ -14,6 +14,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
 )
 
 // ======================================================================================================
@@ -30,12 +31,14 @@ var _ = Describe("Testing Contract", func() {
 	var (
 		// Creating a mock instance of the `IBlockchain` interface.
 		clientMock = contract.NewMockIBlockchain(ctrl)
-		c          = contract.NewContract(clientMock)
+		// every spec mocks the pending nonce of a fresh node, nonces are not allocated locally across them
+		c = contract.NewContract(clientMock, contract.WithSigner(testSigner), contract.WithNonceManager(nil))
 	)
 
 	Context("Test Deploy function", func() {
 		It("Check successful deployment of contract", func() {
 			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+			clientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 			clientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 			clientMock.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
 			clientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
@@ -46,7 +49,7 @@ var _ = Describe("Testing Contract", func() {
 			Expect(err).To(BeNil())
 			Expect(instance).ToNot(BeNil())
 			Expect(addrHash).To(Equal("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf"))
-			Expect(txHash).To(Equal("0x48cc3e57257c690e582516f64320a55d471f52c669eb34a55b9e8ecf6a30128d"))
+			Expect(txHash).To(Equal("0x1feba8545e0947d82ffe9bb31a847a85ef5685ea0c0af283357dab4b7b3b0b7e"))
 		})
 
 		It("Check Error while getting Chain ID", func() {
@@ -67,7 +70,7 @@ var _ = Describe("Testing Contract", func() {
 		})
 
 		It("Check successful execution of Read function", func() {
-			instanceMock.EXPECT().BalanceOf(&bind.CallOpts{}, testAddr).Return(testBalance, nil)
+			instanceMock.EXPECT().BalanceOf(&bind.CallOpts{Context: context.Background()}, testAddr).Return(testBalance, nil)
 
 			bal, err := c.CheckBal(instanceMock, "")
 
@@ -77,7 +80,7 @@ var _ = Describe("Testing Contract", func() {
 		})
 
 		It("Check Error getting Symbol", func() {
-			instanceMock.EXPECT().BalanceOf(&bind.CallOpts{}, testAddr).Return(nil, errors.New("error"))
+			instanceMock.EXPECT().BalanceOf(&bind.CallOpts{Context: context.Background()}, testAddr).Return(nil, errors.New("error"))
 			_, err := c.CheckBal(instanceMock, "")
 
 			Expect(err).ToNot(BeNil())
@@ -85,27 +88,56 @@ var _ = Describe("Testing Contract", func() {
 	})
 
 	Context("Test Reciept function", func() {
-		It("Check successful execution of Reciept function", func() {
-			const txHex = "0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b"
-			os.Setenv("CONTRACT_HASH", txHex)
-			defer os.Unsetenv("CONTRACT_HASH")
+		const txHex = "0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b"
+
+		var (
+			dir string
+			reg *registry.Registry
+			rc  *contract.Contract
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "conploy-registry")
+			Expect(err).To(BeNil())
+
+			reg, err = registry.Open(dir)
+			Expect(err).To(BeNil())
+			Expect(reg.Put(&registry.Record{
+				ChainID: 1,
+				Name:    contract.GoldcoinName,
+				TxHash:  common.HexToHash(txHex),
+			})).To(BeNil())
 
-			clientMock.EXPECT().TransactionReceipt(context.Background(), gomock.Any()).Return(&types.Receipt{
+			rc = contract.NewContract(clientMock, contract.WithRegistry(reg))
+		})
+
+		AfterEach(func() {
+			reg.Close()
+			os.RemoveAll(dir)
+		})
+
+		It("Check successful execution of Reciept function", func() {
+			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+			clientMock.EXPECT().TransactionReceipt(context.Background(), common.HexToHash(txHex)).Return(&types.Receipt{
 				TxHash: common.HexToHash(txHex),
 			}, nil)
 
-			r, err := c.Reciept()
-
+			r, err := rc.Reciept()
 			Expect(err).To(BeNil())
 			Expect(r).NotTo(BeNil())
 		})
 
 		It("Check Error getting Reciept", func() {
+			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
 			clientMock.EXPECT().TransactionReceipt(context.Background(), gomock.Any()).Return(nil, errors.New("error"))
+			_, err := rc.Reciept()
+			Expect(err).ToNot(BeNil())
+		})
 
+		It("Check Error without registry", func() {
 			_, err := c.Reciept()
-
-			Expect(err).ToNot(BeNil())
+			Expect(errors.Is(err, contract.ErrNoRegistry)).To(BeTrue())
 		})
 	})
 
@@ -114,9 +146,10 @@ var _ = Describe("Testing Contract", func() {
 			instanceMock := contract.NewMockIGoldcoin(ctrl)
 
 			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+			clientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 			clientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 			clientMock.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
-			clientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+			instanceMock.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(testBalance, nil)
 			instanceMock.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).Return(&types.Transaction{}, nil)
 
 			tx, err := c.TransferTokens(instanceMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "100")
@@ -128,14 +161,25 @@ var _ = Describe("Testing Contract", func() {
 		It("Invalid Amount Error", func() {
 			instanceMock := contract.NewMockIGoldcoin(ctrl)
 
+			_, err := c.TransferTokens(instanceMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "XXXX")
+
+			Expect(errors.Is(err, contract.ErrInvalidAmount)).To(BeTrue())
+		})
+
+		It("Insufficient Balance Error", func() {
+			instanceMock := contract.NewMockIGoldcoin(ctrl)
+
 			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+			clientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 			clientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 			clientMock.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
-			clientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+			instanceMock.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(big.NewInt(99), nil)
 
-			_, err := c.TransferTokens(instanceMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "XXXX")
+			_, err := c.TransferTokens(instanceMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "100")
 
-			Expect(err).ToNot(BeNil())
+			var balErr *contract.InsufficientBalanceError
+			Expect(errors.As(err, &balErr)).To(BeTrue())
+			Expect(balErr.Balance).To(Equal(big.NewInt(99)))
 		})
 	})
 })
@@ -68,6 +68,21 @@ func (mr *MockIBlockchainMockRecorder) CodeAt(ctx, contract, blockNumber interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CodeAt", reflect.TypeOf((*MockIBlockchain)(nil).CodeAt), ctx, contract, blockNumber)
 }
 
+// BalanceAt mocks base method
+func (m *MockIBlockchain) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, account, blockNumber)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt
+func (mr *MockIBlockchainMockRecorder) BalanceAt(ctx, account, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockIBlockchain)(nil).BalanceAt), ctx, account, blockNumber)
+}
+
 // CallContract mocks base method
 func (m *MockIBlockchain) CallContract(ctx context.Context, call go_ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -278,6 +293,58 @@ func (mr *MockIBlockchainMockRecorder) HeaderByNumber(ctx, number interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockIBlockchain)(nil).HeaderByNumber), ctx, number)
 }
 
+// MockSigner is a mock of Signer interface
+type MockSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignerMockRecorder
+}
+
+// MockSignerMockRecorder is the mock recorder for MockSigner
+type MockSignerMockRecorder struct {
+	mock *MockSigner
+}
+
+// NewMockSigner creates a new mock instance
+func NewMockSigner(ctrl *gomock.Controller) *MockSigner {
+	mock := &MockSigner{ctrl: ctrl}
+	mock.recorder = &MockSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSigner) EXPECT() *MockSignerMockRecorder {
+	return m.recorder
+}
+
+// Address mocks base method
+func (m *MockSigner) Address() common.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Address")
+	ret0, _ := ret[0].(common.Address)
+	return ret0
+}
+
+// Address indicates an expected call of Address
+func (mr *MockSignerMockRecorder) Address() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Address", reflect.TypeOf((*MockSigner)(nil).Address))
+}
+
+// SignTx mocks base method
+func (m *MockSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignTx", tx, chainID)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTx indicates an expected call of SignTx
+func (mr *MockSignerMockRecorder) SignTx(tx, chainID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTx", reflect.TypeOf((*MockSigner)(nil).SignTx), tx, chainID)
+}
+
 // MockIGoldcoin is a mock of IGoldcoin interface
 type MockIGoldcoin struct {
 	ctrl     *gomock.Controller
@@ -330,3 +397,138 @@ func (mr *MockIGoldcoinMockRecorder) BalanceOf(opts, account interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceOf", reflect.TypeOf((*MockIGoldcoin)(nil).BalanceOf), opts, account)
 }
+
+// Decimals mocks base method
+func (m *MockIGoldcoin) Decimals(opts *bind.CallOpts) (uint8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decimals", opts)
+	ret0, _ := ret[0].(uint8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decimals indicates an expected call of Decimals
+func (mr *MockIGoldcoinMockRecorder) Decimals(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decimals", reflect.TypeOf((*MockIGoldcoin)(nil).Decimals), opts)
+}
+
+// Symbol mocks base method
+func (m *MockIGoldcoin) Symbol(opts *bind.CallOpts) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Symbol", opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Symbol indicates an expected call of Symbol
+func (mr *MockIGoldcoinMockRecorder) Symbol(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Symbol", reflect.TypeOf((*MockIGoldcoin)(nil).Symbol), opts)
+}
+
+// Approve mocks base method
+func (m *MockIGoldcoin) Approve(opts *bind.TransactOpts, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", opts, spender, amount)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve
+func (mr *MockIGoldcoinMockRecorder) Approve(opts, spender, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockIGoldcoin)(nil).Approve), opts, spender, amount)
+}
+
+// Allowance mocks base method
+func (m *MockIGoldcoin) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allowance", opts, owner, spender)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allowance indicates an expected call of Allowance
+func (mr *MockIGoldcoinMockRecorder) Allowance(opts, owner, spender interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allowance", reflect.TypeOf((*MockIGoldcoin)(nil).Allowance), opts, owner, spender)
+}
+
+// TransferFrom mocks base method
+func (m *MockIGoldcoin) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferFrom", opts, from, to, amount)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferFrom indicates an expected call of TransferFrom
+func (mr *MockIGoldcoinMockRecorder) TransferFrom(opts, from, to, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferFrom", reflect.TypeOf((*MockIGoldcoin)(nil).TransferFrom), opts, from, to, amount)
+}
+
+// IncreaseAllowance mocks base method
+func (m *MockIGoldcoin) IncreaseAllowance(opts *bind.TransactOpts, spender common.Address, addedValue *big.Int) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseAllowance", opts, spender, addedValue)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseAllowance indicates an expected call of IncreaseAllowance
+func (mr *MockIGoldcoinMockRecorder) IncreaseAllowance(opts, spender, addedValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseAllowance", reflect.TypeOf((*MockIGoldcoin)(nil).IncreaseAllowance), opts, spender, addedValue)
+}
+
+// DecreaseAllowance mocks base method
+func (m *MockIGoldcoin) DecreaseAllowance(opts *bind.TransactOpts, spender common.Address, subtractedValue *big.Int) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecreaseAllowance", opts, spender, subtractedValue)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecreaseAllowance indicates an expected call of DecreaseAllowance
+func (mr *MockIGoldcoinMockRecorder) DecreaseAllowance(opts, spender, subtractedValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecreaseAllowance", reflect.TypeOf((*MockIGoldcoin)(nil).DecreaseAllowance), opts, spender, subtractedValue)
+}
+
+// Name mocks base method
+func (m *MockIGoldcoin) Name(opts *bind.CallOpts) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name", opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Name indicates an expected call of Name
+func (mr *MockIGoldcoinMockRecorder) Name(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockIGoldcoin)(nil).Name), opts)
+}
+
+// TotalSupply mocks base method
+func (m *MockIGoldcoin) TotalSupply(opts *bind.CallOpts) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalSupply", opts)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TotalSupply indicates an expected call of TotalSupply
+func (mr *MockIGoldcoinMockRecorder) TotalSupply(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalSupply", reflect.TypeOf((*MockIGoldcoin)(nil).TotalSupply), opts)
+}
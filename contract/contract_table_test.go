@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"math/big"
-	"os"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,15 +11,14 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/gopherine/evmos-conploy/address"
 	"github.com/gopherine/evmos-conploy/contract"
 	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/registry"
 )
 
 // `TestDeploy` is a test function that tests the `Deploy` function
 func (ts *TableSuite) TestDeploy() {
-	// Setting a mock environment variable for testing.
-	os.Setenv("OWNER_PRIVATEKEY", testKeyStr)
-	defer os.Unsetenv("OWNER_PRIVATEKEY")
 	// Mock deploy function that returns a `common.Address`, a `*types.Transaction`, a `*goldcoin.Goldcoin` and an
 	// `error`.
 	var deployFunc = func(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *goldcoin.Goldcoin, error) {
@@ -42,20 +40,24 @@ func (ts *TableSuite) TestDeploy() {
 	// Creating a slice of structs that contains the name of the test, the deploy function, the prepare
 	// function and the wantErr boolean.
 	subtests := []struct {
-		name    string
-		deploy  func(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *goldcoin.Goldcoin, error)
-		prepare func(m *contract.MockIBlockchain)
-		wantErr bool
+		name     string
+		deploy   func(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *goldcoin.Goldcoin, error)
+		prepare  func(m *contract.MockIBlockchain)
+		noSigner bool
+		wantErr  bool
 	}{
 		{
 			name:   "Deploying smart contract Successfully",
 			deploy: deployFunc,
 			prepare: func(m *contract.MockIBlockchain) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
 				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
 				m.EXPECT().SendTransaction(context.Background(), gomock.Any()).Return(nil)
+				// chain id lookup while recording the deployment in the registry
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
 			},
 			wantErr: false,
 		},
@@ -78,6 +80,7 @@ func (ts *TableSuite) TestDeploy() {
 			deploy: deployFunc,
 			prepare: func(m *contract.MockIBlockchain) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(nil, errors.New("suggest gas price error"))
 			},
 			wantErr: true,
@@ -87,6 +90,7 @@ func (ts *TableSuite) TestDeploy() {
 			deploy: deployFunc,
 			prepare: func(m *contract.MockIBlockchain) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(0), errors.New("error"))
 			},
@@ -97,6 +101,7 @@ func (ts *TableSuite) TestDeploy() {
 			deploy: deployFunc,
 			prepare: func(m *contract.MockIBlockchain) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
 				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(0), errors.New("error"))
@@ -108,6 +113,7 @@ func (ts *TableSuite) TestDeploy() {
 			deploy: deployFunc,
 			prepare: func(m *contract.MockIBlockchain) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
 				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
@@ -115,14 +121,10 @@ func (ts *TableSuite) TestDeploy() {
 			},
 			wantErr: true,
 		}, {
-			name:   "Error Invalid Private Key",
-			deploy: deployFunc,
-			prepare: func(m *contract.MockIBlockchain) {
-				// overwrite private key env variable for checking HexToECDSA error
-				os.Setenv("OWNER_PRIVATEKEY", "invalid_key")
-				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
-			},
-			wantErr: true,
+			name:     "Error No Signer",
+			deploy:   deployFunc,
+			noSigner: true,
+			wantErr:  true,
 		},
 	}
 
@@ -137,12 +139,23 @@ func (ts *TableSuite) TestDeploy() {
 				tt.prepare(ts.ClientMock)
 			}
 
-			if instance, addrHash, txHash, err := ts.Contract.Deploy(); (err != nil) != tt.wantErr {
+			c := ts.Contract
+			if tt.noSigner {
+				c = contract.NewContract(ts.ClientMock)
+			}
+
+			if instance, addrHash, txHash, err := c.Deploy(); (err != nil) != tt.wantErr {
 				ts.Errorf(err, "Expected %v got %v", tt.wantErr, err)
 			} else if !tt.wantErr {
 				assert.NotNil(ts.T(), instance)
 				assert.Equal(ts.T(), addrHash, "0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
-				assert.Equal(ts.T(), txHash, "0x48cc3e57257c690e582516f64320a55d471f52c669eb34a55b9e8ecf6a30128d")
+				assert.Equal(ts.T(), txHash, "0x1feba8545e0947d82ffe9bb31a847a85ef5685ea0c0af283357dab4b7b3b0b7e")
+
+				rec, err := ts.Registry.Latest(1, contract.GoldcoinName)
+				assert.NoError(ts.T(), err)
+				assert.Equal(ts.T(), addrHash, rec.Address.Hex())
+				assert.Equal(ts.T(), txHash, rec.TxHash.Hex())
+				assert.Equal(ts.T(), testAddr, rec.Deployer)
 			} else {
 				assert.Error(ts.T(), err)
 			}
@@ -152,34 +165,43 @@ func (ts *TableSuite) TestDeploy() {
 
 // A test function that tests the `CheckBal` function.
 func (ts *TableSuite) TestCheckBal() {
-	// Setting a mock environment variable for testing.
-	os.Setenv("OWNER_PRIVATEKEY", testKeyStr)
-	defer os.Unsetenv("OWNER_PRIVATEKEY")
-
 	subtests := []struct {
-		name    string
-		prepare func(m *contract.MockIGoldcoin)
-		wantErr bool
+		name     string
+		address  string
+		prepare  func(m *contract.MockIGoldcoin)
+		noSigner bool
+		wantErr  bool
 	}{
 		{
 			name: "Read from instance and get symbol and balance",
 			prepare: func(m *contract.MockIGoldcoin) {
-				m.EXPECT().BalanceOf(&bind.CallOpts{}, testAddr).Return(testBalance, nil)
+				m.EXPECT().BalanceOf(&bind.CallOpts{Context: context.Background()}, testAddr).Return(testBalance, nil)
 			},
 			wantErr: false,
 		},
 		{
-			name: "Error Balanceof",
+			name:    "Balance of bech32 address",
+			address: address.ToBech32(testAddr),
 			prepare: func(m *contract.MockIGoldcoin) {
-				m.EXPECT().BalanceOf(&bind.CallOpts{}, testAddr).Return(nil, errors.New("error"))
+				m.EXPECT().BalanceOf(&bind.CallOpts{Context: context.Background()}, testAddr).Return(testBalance, nil)
 			},
+			wantErr: false,
+		},
+		{
+			name:    "Error invalid address",
+			address: "evmos1invalid",
 			wantErr: true,
-		}, {
-			name: "Error Invalid Privatekey",
+		},
+		{
+			name: "Error Balanceof",
 			prepare: func(m *contract.MockIGoldcoin) {
-				os.Setenv("OWNER_PRIVATEKEY", "INVALID_KEY")
+				m.EXPECT().BalanceOf(&bind.CallOpts{Context: context.Background()}, testAddr).Return(nil, errors.New("error"))
 			},
 			wantErr: true,
+		}, {
+			name:     "Error No Signer",
+			noSigner: true,
+			wantErr:  true,
 		},
 	}
 
@@ -191,7 +213,12 @@ func (ts *TableSuite) TestCheckBal() {
 				tt.prepare(ts.GoldcoinMock)
 			}
 
-			bal, err := ts.Contract.CheckBal(ts.GoldcoinMock, "")
+			c := ts.Contract
+			if tt.noSigner {
+				c = contract.NewContract(ts.ClientMock)
+			}
+
+			bal, err := c.CheckBal(ts.GoldcoinMock, tt.address)
 
 			if (err != nil) != tt.wantErr {
 				ts.Errorf(err, "Unexpected Result")
@@ -204,23 +231,52 @@ func (ts *TableSuite) TestCheckBal() {
 
 // A test function that tests the `Load` function.
 func (ts *TableSuite) TestLoad() {
+	err := ts.Registry.Put(&registry.Record{
+		ChainID: 2,
+		Name:    contract.GoldcoinName,
+		Address: testAddr,
+	})
+	ts.Require().NoError(err)
+
 	subtests := []struct {
 		name    string
+		prepare func(m *contract.MockIBlockchain)
 		wantErr bool
 	}{
 		{
-			name:    "Loads goldcoin instance",
+			name: "Loads goldcoin instance",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(2), nil)
+			},
 			wantErr: false,
 		},
+		{
+			name: "Error contract not deployed on chain",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(3), nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error ChainID",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(nil, errors.New("chain id error"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range subtests {
 		ts.Run(tt.name, func() {
-			os.Setenv("CONTRACT_ADDRESS", testAddr.Hex())
-			defer os.Unsetenv("CONTRACT_ADDRESS")
+			tt.prepare(ts.ClientMock)
 
-			instance := ts.Contract.Load()
-			assert.NotNil(ts.T(), instance)
+			instance, err := ts.Contract.Load()
+			if tt.wantErr {
+				assert.Error(ts.T(), err)
+			} else {
+				assert.NoError(ts.T(), err)
+				assert.NotNil(ts.T(), instance)
+			}
 		})
 	}
 }
@@ -228,8 +284,14 @@ func (ts *TableSuite) TestLoad() {
 // This function is testing the `Reciept` function.
 func (ts *TableSuite) TestReciept() {
 	const txHex = "0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b"
-	os.Setenv("CONTRACT_HASH", txHex)
-	defer os.Unsetenv("CONTRACT_HASH")
+
+	err := ts.Registry.Put(&registry.Record{
+		ChainID: 4,
+		Name:    contract.GoldcoinName,
+		Address: testAddr,
+		TxHash:  common.HexToHash(txHex),
+	})
+	ts.Require().NoError(err)
 
 	subtests := []struct {
 		name    string
@@ -239,8 +301,10 @@ func (ts *TableSuite) TestReciept() {
 		{
 			name: "Successfully generate reciept",
 			prepare: func(m *contract.MockIBlockchain) {
-				m.EXPECT().TransactionReceipt(ts.Ctx, gomock.Any()).Return(&types.Receipt{
-					TxHash: common.HexToHash(txHex),
+				m.EXPECT().ChainID(ts.Ctx).Return(big.NewInt(4), nil)
+				m.EXPECT().TransactionReceipt(ts.Ctx, common.HexToHash(txHex)).Return(&types.Receipt{
+					TxHash:      common.HexToHash(txHex),
+					BlockNumber: big.NewInt(10),
 				}, nil)
 			},
 			wantErr: false,
@@ -248,8 +312,15 @@ func (ts *TableSuite) TestReciept() {
 		{
 			name: "Error generating reciept",
 			prepare: func(m *contract.MockIBlockchain) {
-				os.Setenv("CONTRACT_HASH", "INVALID_HASH")
-				ts.ClientMock.EXPECT().TransactionReceipt(ts.Ctx, gomock.Any()).Return(nil, errors.New("error"))
+				m.EXPECT().ChainID(ts.Ctx).Return(big.NewInt(4), nil)
+				m.EXPECT().TransactionReceipt(ts.Ctx, gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error contract not deployed on chain",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(ts.Ctx).Return(big.NewInt(5), nil)
 			},
 			wantErr: true,
 		},
@@ -268,21 +339,24 @@ func (ts *TableSuite) TestReciept() {
 			} else if !tt.wantErr {
 				assert.Equal(ts.T(), common.HexToHash(txHex), r.TxHash)
 				ts.NoError(err)
+
+				// block number is persisted once the deployment is mined
+				rec, err := ts.Registry.Latest(4, contract.GoldcoinName)
+				ts.NoError(err)
+				assert.Equal(ts.T(), uint64(10), rec.BlockNumber)
 			}
 		})
 	}
 }
 
 func (ts *TableSuite) TestTransferTokens() {
-	// Setting a mock environment variable for testing.
-	os.Setenv("OWNER_PRIVATEKEY", testKeyStr)
-	defer os.Unsetenv("OWNER_PRIVATEKEY")
-
 	subTests := []struct {
-		name    string
-		wantErr bool
-		amount  string
-		prepare func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin)
+		name     string
+		wantErr  bool
+		reciever string
+		amount   string
+		prepare  func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin)
+		errIs    error
 	}{
 		{
 			name:    "Transfer token successfully",
@@ -290,9 +364,10 @@ func (ts *TableSuite) TestTransferTokens() {
 			amount:  "100",
 			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
-				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(testBalance, nil)
 				mg.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).Return(&types.Transaction{}, nil)
 			},
 		}, {
@@ -301,21 +376,58 @@ func (ts *TableSuite) TestTransferTokens() {
 			amount:  "100",
 			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
-				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(testBalance, nil)
 				mg.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 			},
 		}, {
 			name:    "Error Invalid Amount",
-			wantErr: false,
+			wantErr: true,
 			amount:  "INVALID",
+			errIs:   contract.ErrInvalidAmount,
+		}, {
+			name:    "Error Negative Amount",
+			wantErr: true,
+			amount:  "-100",
+			errIs:   contract.ErrNegativeAmount,
+		}, {
+			name:    "Error Insufficient Balance",
+			wantErr: true,
+			amount:  "100",
 			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
 				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
 				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
 				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
-				m.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(big.NewInt(10), nil)
+			},
+			errIs: contract.ErrInsufficientBalance,
+		}, {
+			name:     "Transfer to bech32 address",
+			reciever: "evmos1gkfd37xhkqq7wt9jdfe7f7scq6j343uaerstl5",
+			amount:   "100",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(testBalance, nil)
+				mg.EXPECT().Transfer(gomock.Any(), common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d"), big.NewInt(100)).Return(&types.Transaction{}, nil)
 			},
+		}, {
+			name:     "Error Invalid Address",
+			reciever: "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac7",
+			amount:   "100",
+			wantErr:  true,
+			errIs:    contract.ErrInvalidAddress,
+		}, {
+			name:     "Error Zero Address",
+			reciever: "0x0000000000000000000000000000000000000000",
+			amount:   "100",
+			wantErr:  true,
+			errIs:    contract.ErrZeroAddress,
 		}, {
 			name:   "Error ChainID",
 			amount: "100",
@@ -332,10 +444,17 @@ func (ts *TableSuite) TestTransferTokens() {
 				tt.prepare(ts.ClientMock, ts.GoldcoinMock)
 			}
 
-			tx, err := ts.Contract.TransferTokens(ts.GoldcoinMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", tt.amount)
+			reciever := tt.reciever
+			if reciever == "" {
+				reciever = "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d"
+			}
+
+			tx, err := ts.Contract.TransferTokens(ts.GoldcoinMock, reciever, tt.amount)
 
 			if (err != nil) != tt.wantErr {
 				ts.Errorf(err, "Unexpected Result")
+			} else if tt.wantErr && tt.errIs != nil {
+				assert.ErrorIs(ts.T(), err, tt.errIs)
 			} else if tt.wantErr {
 				assert.Error(ts.T(), err)
 			} else {
@@ -345,3 +464,80 @@ func (ts *TableSuite) TestTransferTokens() {
 		})
 	}
 }
+
+// contextKey is used to tell the context handed to the contract module apart from `context.Background()`
+type contextKey struct{}
+
+// A test function that tests the context given to the `...Context` variants reaches the node and contract calls.
+func (ts *TableSuite) TestContextPropagation() {
+	ctx := context.WithValue(context.Background(), contextKey{}, "conploy")
+
+	ts.Run("TransferTokensContext", func() {
+		ts.ClientMock.EXPECT().ChainID(ctx).Return(big.NewInt(001), nil)
+		ts.ClientMock.EXPECT().HeaderByNumber(ctx, nil).Return(&types.Header{}, nil)
+		ts.ClientMock.EXPECT().SuggestGasPrice(ctx).Return(big.NewInt(1000), nil)
+		ts.ClientMock.EXPECT().PendingNonceAt(ctx, gomock.Any()).Return(uint64(1), nil)
+		ts.GoldcoinMock.EXPECT().BalanceOf(&bind.CallOpts{Context: ctx}, testAddr).Return(testBalance, nil)
+		ts.GoldcoinMock.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(auth *bind.TransactOpts, _ common.Address, _ *big.Int) (*types.Transaction, error) {
+				assert.Equal(ts.T(), ctx, auth.Context)
+				return &types.Transaction{}, nil
+			})
+
+		_, err := ts.Contract.TransferTokensContext(ctx, ts.GoldcoinMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "100")
+		assert.NoError(ts.T(), err)
+	})
+
+	ts.Run("CheckBalContext", func() {
+		ts.GoldcoinMock.EXPECT().BalanceOf(&bind.CallOpts{Context: ctx}, testAddr).Return(testBalance, nil)
+
+		bal, err := ts.Contract.CheckBalContext(ctx, ts.GoldcoinMock, "")
+		assert.NoError(ts.T(), err)
+		assert.Equal(ts.T(), testBalance, bal)
+	})
+
+	ts.Run("Cancelled context", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		ts.ClientMock.EXPECT().ChainID(cancelled).Return(nil, cancelled.Err())
+
+		_, _, _, err := ts.Contract.DeployContext(cancelled)
+		assert.ErrorIs(ts.T(), err, context.Canceled)
+	})
+}
+
+// A test function that tests transactions are signed through the configured `Signer`.
+func (ts *TableSuite) TestSigner() {
+	signerMock := contract.NewMockSigner(ts.Ctrl)
+	c := contract.NewContract(ts.ClientMock, contract.WithSigner(signerMock), contract.WithNonceManager(nil))
+
+	ts.Run("Signs deploy transaction with signer", func() {
+		signerMock.EXPECT().Address().Return(testAddr).AnyTimes()
+		signerMock.EXPECT().SignTx(gomock.Any(), big.NewInt(001)).DoAndReturn(testSigner.SignTx)
+
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+		ts.ClientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+		ts.ClientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+		ts.ClientMock.EXPECT().PendingNonceAt(context.Background(), testAddr).Return(uint64(1), nil)
+		ts.ClientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+		ts.ClientMock.EXPECT().SendTransaction(context.Background(), gomock.Any()).Return(nil)
+
+		_, _, txHash, err := c.Deploy()
+		assert.NoError(ts.T(), err)
+		assert.Equal(ts.T(), "0x1feba8545e0947d82ffe9bb31a847a85ef5685ea0c0af283357dab4b7b3b0b7e", txHash)
+	})
+
+	ts.Run("Error signing transaction", func() {
+		signerMock.EXPECT().SignTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("signer locked"))
+
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+		ts.ClientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+		ts.ClientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+		ts.ClientMock.EXPECT().PendingNonceAt(context.Background(), testAddr).Return(uint64(1), nil)
+		ts.ClientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).Return(uint64(100), nil)
+
+		_, _, _, err := c.Deploy()
+		assert.EqualError(ts.T(), err, "signer locked")
+	})
+}
@@ -0,0 +1,240 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// DeterministicDeployer is the deterministic deployment proxy of https://github.com/Arachnid/deterministic-deployment-proxy.
+// It is deployed by a presigned transaction without chain id, so it has the same address on every chain, and it
+// deploys the init code following a 32 byte salt in its calldata with CREATE2.
+var DeterministicDeployer = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+var (
+	// deployerSender is the keyless account the presigned transaction is sent from, it must hold its gas cost
+	deployerSender = common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+	// deployerTx is the presigned transaction deploying the proxy, 100000 gas at 100 gwei
+	deployerTx = hexutil.MustDecode("0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222")
+	// deployerCost is the wei the sender of the presigned transaction needs
+	deployerCost = new(big.Int).Mul(big.NewInt(100_000), big.NewInt(100_000_000_000))
+)
+
+var (
+	// ErrAlreadyDeployed is returned when a CREATE2 deployment targets an address that already holds code
+	ErrAlreadyDeployed = errors.New("contract already deployed")
+	// ErrNoDeployer is returned when a CREATE2 deployment is made on a chain without the deterministic deployer
+	ErrNoDeployer = errors.New("deterministic deployer not deployed")
+)
+
+// ParseSalt parses a CREATE2 salt, 0x hex of up to 32 bytes is left padded and any other text, eg. `goldcoin-v1`,
+// is hashed with keccak256.
+func ParseSalt(s string) (common.Hash, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return crypto.Keccak256Hash([]byte(s)), nil
+	}
+
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) > common.HashLength {
+		if err == nil {
+			err = fmt.Errorf("%d bytes do not fit in 32", len(b))
+		}
+
+		return common.Hash{}, &ArgumentError{Name: "salt", Type: "bytes32", Input: s, Err: err}
+	}
+
+	return common.BytesToHash(b), nil
+}
+
+// Create2Address returns the address the deterministic deployer deploys the init code to with the given salt.
+func Create2Address(salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(DeterministicDeployer, salt, crypto.Keccak256(initCode))
+}
+
+// InitCode returns the creation bytecode of the artifact followed by its packed constructor arguments, converted
+// like with `DeployArtifact`.
+func (a *Artifact) InitCode(args ...string) ([]byte, error) {
+	params, err := ParseArguments(a.ABI.Constructor.Inputs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	packed, err := a.ABI.Pack("", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{}, a.Bin...), packed...), nil
+}
+
+// Create2Address precomputes the address `DeployCreate2` deploys the artifact to, no node is needed.
+func (a *Artifact) Create2Address(salt common.Hash, args ...string) (common.Address, error) {
+	initCode, err := a.InitCode(args...)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return Create2Address(salt, initCode), nil
+}
+
+// DeployCreate2 deploys the artifact through the deterministic deployer, so the same artifact, constructor
+// arguments and salt land at the same address on every chain. It fails with `ErrNoDeployer` when the chain does not
+// have the deployer, which `EnsureDeployer` sets up. Deploying to an address that already holds code fails with
+// `ErrAlreadyDeployed` along with the address. The deployment is recorded under the artifact name with its salt.
+func (c *Contract) DeployCreate2(a *Artifact, salt common.Hash, args ...string) (common.Address, *types.Transaction, error) {
+	return c.DeployCreate2Context(context.Background(), a, salt, args...)
+}
+
+// DeployCreate2Context is like `DeployCreate2` but every node call is bound to the given context.
+func (c *Contract) DeployCreate2Context(ctx context.Context, a *Artifact, salt common.Hash, args ...string) (common.Address, *types.Transaction, error) {
+	return c.deployCreate2(ctx, a, salt, nil, args...)
+}
+
+// deployCreate2 deploys the artifact through the deterministic deployer, the deploy transaction is kept with save
+// before it is sent when save is given, see `sendSaved`.
+func (c *Contract) deployCreate2(ctx context.Context, a *Artifact, salt common.Hash, save func(tx *types.Transaction) error, args ...string) (common.Address, *types.Transaction, error) {
+	initCode, err := a.InitCode(args...)
+	if err != nil {
+		log.Err(err).Msgf("invalid constructor arguments of %s", a.Name)
+		return common.Address{}, nil, err
+	}
+
+	address := Create2Address(salt, initCode)
+
+	code, err := c.Client.CodeAt(ctx, address, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get contract code")
+		return common.Address{}, nil, err
+	}
+
+	if len(code) > 0 {
+		err := fmt.Errorf("%w: %s at %s", ErrAlreadyDeployed, a.Name, address.Hex())
+		log.Err(err).Msg("unable to deploy")
+		return address, nil, err
+	}
+
+	// setting the deployer up costs funds which may be lost, it is left to an explicit `EnsureDeployer`
+	if ok, err := c.deployerDeployed(ctx); err != nil {
+		return common.Address{}, nil, err
+	} else if !ok {
+		err := fmt.Errorf("%w at %s", ErrNoDeployer, DeterministicDeployer.Hex())
+		log.Err(err).Msgf("Unable to deploy %s", a.Name)
+		return common.Address{}, nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	defer release()
+
+	proxy := bind.NewBoundContract(DeterministicDeployer, abi.ABI{}, c.backend(), c.backend(), c.backend())
+
+	auth.NoSend = save != nil
+
+	tx, err := proxy.RawTransact(auth, append(salt.Bytes(), initCode...))
+	if err != nil {
+		log.Err(err).Msgf("Unable to deploy %s", a.Name)
+		return common.Address{}, nil, err
+	}
+
+	if err := c.sendSaved(ctx, tx, save); err != nil {
+		return common.Address{}, nil, err
+	}
+
+	c.recordSubmitted(ctx, a.Name, string(a.abiJSON), hexutil.Encode(a.Bin), auth.From, address, tx, &salt)
+
+	return address, tx, nil
+}
+
+// EnsureDeployer deploys the deterministic deployer when the chain does not have it, it reports whether it had
+// to. The sender of the presigned transaction is funded from the signer first and both transactions are waited
+// for, as the deployer must exist before anything is deployed through it. The funds are lost when the node then
+// rejects the presigned transaction for having no chain id, as Evmos does unless its evm params allow unprotected
+// transactions, nobody holds the key of the sender.
+func (c *Contract) EnsureDeployer() (bool, error) {
+	return c.EnsureDeployerContext(context.Background())
+}
+
+// EnsureDeployerContext is like `EnsureDeployer` but every node call is bound to the given context.
+func (c *Contract) EnsureDeployerContext(ctx context.Context) (bool, error) {
+	if ok, err := c.deployerDeployed(ctx); err != nil || ok {
+		return false, err
+	}
+
+	log.Info().Msgf("Deterministic deployer missing at %s, deploying it", DeterministicDeployer.Hex())
+
+	// the presigned transaction is valid on every chain, make sure it is sent to the intended one
+	if _, err := c.sendingChainID(ctx); err != nil {
+		return false, err
+	}
+
+	balance, err := c.Client.BalanceAt(ctx, deployerSender, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get deterministic deployer sender balance")
+		return false, err
+	}
+
+	if balance.Cmp(deployerCost) < 0 {
+		auth, release, err := c.getTxSigner(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer release()
+
+		// a plain value transfer, there is no code at the sender to estimate gas against
+		auth.Value = new(big.Int).Sub(deployerCost, balance)
+		auth.GasLimit = 21_000
+
+		sender := bind.NewBoundContract(deployerSender, abi.ABI{}, c.backend(), c.backend(), c.backend())
+
+		fund, err := sender.RawTransact(auth, nil)
+		if err != nil {
+			log.Err(err).Msg("unable to fund deterministic deployer sender")
+			return false, err
+		}
+
+		if _, err := c.WaitMinedContext(ctx, fund.Hash(), WaitOpts{}); err != nil {
+			return false, err
+		}
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(deployerTx); err != nil {
+		return false, err
+	}
+
+	if err := c.Client.SendTransaction(ctx, tx); err != nil {
+		// evmos only accepts transactions without chain id when its evm params allow unprotected transactions
+		err = fmt.Errorf("presigned deployer transaction without chain id rejected: %w", err)
+		log.Err(err).Msg("unable to deploy deterministic deployer")
+		return false, err
+	}
+
+	if _, err := c.WaitMinedContext(ctx, tx.Hash(), WaitOpts{}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// deployerDeployed reports whether the chain has the deterministic deployer.
+func (c *Contract) deployerDeployed(ctx context.Context) (bool, error) {
+	code, err := c.Client.CodeAt(ctx, DeterministicDeployer, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get deterministic deployer code")
+		return false, err
+	}
+
+	return len(code) > 0, nil
+}
@@ -0,0 +1,177 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// A test function that tests parsing CREATE2 salts.
+func (ts *TableSuite) TestParseSalt() {
+	subtests := []struct {
+		name    string
+		input   string
+		want    common.Hash
+		wantErr error
+	}{
+		{name: "Hex is left padded", input: "0x01", want: common.HexToHash("0x01")},
+		{name: "Full hash", input: common.HexToHash("0xabcd").Hex(), want: common.HexToHash("0xabcd")},
+		{name: "Text is hashed", input: "goldcoin-v1", want: crypto.Keccak256Hash([]byte("goldcoin-v1"))},
+		{name: "Invalid hex", input: "0xzz", wantErr: contract.ErrInvalidArgument},
+		{name: "Too long", input: "0x" + common.Bytes2Hex(make([]byte, 33)), wantErr: contract.ErrInvalidArgument},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			salt, err := contract.ParseSalt(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				return
+			}
+
+			ts.Require().NoError(err)
+			assert.Equal(ts.T(), tt.want, salt)
+		})
+	}
+}
+
+// A test function that tests deterministic deployments through the deployment proxy.
+func (ts *TableSuite) TestDeployCreate2() {
+	salt := common.HexToHash("0x01")
+	goldcoin := contract.GoldcoinArtifact()
+
+	initCode, err := goldcoin.InitCode()
+	ts.Require().NoError(err)
+
+	deployed := crypto.CreateAddress2(contract.DeterministicDeployer, salt, crypto.Keccak256(initCode))
+	sender := common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+
+	ts.Run("Precomputes the address without a node", func() {
+		addr, err := goldcoin.Create2Address(salt)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), deployed, addr)
+
+		vault, err := contract.ParseArtifact("Vault", []byte(vaultABI), []byte("6080604052"))
+		ts.Require().NoError(err)
+
+		args := []string{"vault", holderAddr.Hex(), "18", "-5", "16", "true", "0x01", "[1]", `["` + testAddr.Hex() + `","` + spenderAddr.Hex() + `"]`}
+		first, err := vault.Create2Address(salt, args...)
+		ts.Require().NoError(err)
+
+		args[0] = "other"
+		second, err := vault.Create2Address(salt, args...)
+		ts.Require().NoError(err)
+		assert.NotEqual(ts.T(), first, second, "constructor arguments are part of the init code")
+
+		_, err = vault.Create2Address(salt, "vault")
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidArgument)
+	})
+
+	// newMock expects the node calls every transaction makes and collects the sent transactions
+	newMock := func(sent *[]*types.Transaction) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(24), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{}, nil).AnyTimes()
+		m.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(1000), nil).AnyTimes()
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).Return(uint64(3), nil).AnyTimes()
+		// the pending nonce lags behind the transactions sent, which the node knows about
+		m.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).Return(types.NewTx(&types.LegacyTx{}), true, nil).AnyTimes()
+		m.EXPECT().PendingCodeAt(gomock.Any(), contract.DeterministicDeployer).Return([]byte{0x60}, nil).AnyTimes()
+		m.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(uint64(900000), nil).AnyTimes()
+		m.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			*sent = append(*sent, tx)
+			return nil
+		}).AnyTimes()
+		m.EXPECT().TransactionReceipt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+			return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(5)}, nil
+		}).AnyTimes()
+
+		return m
+	}
+
+	ts.Run("Deploys through the existing deployer", func() {
+		var sent []*types.Transaction
+		m := newMock(&sent)
+		m.EXPECT().CodeAt(gomock.Any(), deployed, nil).Return(nil, nil)
+		m.EXPECT().CodeAt(gomock.Any(), contract.DeterministicDeployer, nil).Return([]byte{0x60}, nil)
+
+		c := contract.NewContract(m, contract.WithRegistry(ts.Registry), contract.WithSigner(testSigner))
+
+		addr, tx, err := c.DeployCreate2(goldcoin, salt)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), deployed, addr)
+		ts.Require().Len(sent, 1)
+		assert.Equal(ts.T(), tx, sent[0])
+		assert.Equal(ts.T(), &contract.DeterministicDeployer, tx.To())
+		assert.Equal(ts.T(), append(salt.Bytes(), initCode...), tx.Data())
+
+		rec, err := ts.Registry.Latest(24, contract.GoldcoinName)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), deployed, rec.Address)
+		assert.Equal(ts.T(), &salt, rec.Salt)
+	})
+
+	ts.Run("Refuses to deploy without the deployer", func() {
+		var sent []*types.Transaction
+		m := newMock(&sent)
+		m.EXPECT().CodeAt(gomock.Any(), deployed, nil).Return(nil, nil)
+		m.EXPECT().CodeAt(gomock.Any(), contract.DeterministicDeployer, nil).Return(nil, nil)
+
+		_, _, err := contract.NewContract(m, contract.WithSigner(testSigner)).DeployCreate2(goldcoin, salt)
+		assert.ErrorIs(ts.T(), err, contract.ErrNoDeployer)
+		assert.Empty(ts.T(), sent, "the keyless sender is not funded")
+	})
+
+	ts.Run("Sets up the deployer when missing", func() {
+		var sent []*types.Transaction
+		m := newMock(&sent)
+		m.EXPECT().CodeAt(gomock.Any(), deployed, nil).Return(nil, nil)
+		gomock.InOrder(
+			m.EXPECT().CodeAt(gomock.Any(), contract.DeterministicDeployer, nil).Return(nil, nil),
+			m.EXPECT().CodeAt(gomock.Any(), contract.DeterministicDeployer, nil).Return([]byte{0x60}, nil),
+		)
+		m.EXPECT().BalanceAt(gomock.Any(), sender, nil).Return(big.NewInt(4e15), nil)
+
+		c := contract.NewContract(m, contract.WithSigner(testSigner))
+
+		deployedNow, err := c.EnsureDeployer()
+		ts.Require().NoError(err)
+		assert.True(ts.T(), deployedNow)
+
+		_, _, err = c.DeployCreate2(goldcoin, salt)
+		ts.Require().NoError(err)
+		ts.Require().Len(sent, 3)
+
+		fund, presigned, deploy := sent[0], sent[1], sent[2]
+		assert.Equal(ts.T(), &sender, fund.To())
+		assert.Equal(ts.T(), big.NewInt(6e15), fund.Value(), "only the missing gas cost is sent")
+
+		from, err := types.Sender(types.HomesteadSigner{}, presigned)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), sender, from)
+		assert.Nil(ts.T(), presigned.To())
+		assert.Equal(ts.T(), contract.DeterministicDeployer, crypto.CreateAddress(from, presigned.Nonce()))
+
+		assert.Equal(ts.T(), &contract.DeterministicDeployer, deploy.To())
+	})
+
+	ts.Run("Address already holds code", func() {
+		var sent []*types.Transaction
+		m := newMock(&sent)
+		m.EXPECT().CodeAt(gomock.Any(), deployed, nil).Return([]byte{0x60}, nil)
+
+		addr, _, err := contract.NewContract(m, contract.WithSigner(testSigner)).DeployCreate2(goldcoin, salt)
+		assert.ErrorIs(ts.T(), err, contract.ErrAlreadyDeployed)
+		assert.Equal(ts.T(), deployed, addr)
+		assert.Empty(ts.T(), sent)
+	})
+}
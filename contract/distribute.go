@@ -0,0 +1,504 @@
+package contract
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Statuses of the payments reported by `Distribute`
+const (
+	// PaymentPaid is a payment whose transfer was sent, or resumed, and mined by this run
+	PaymentPaid = "paid"
+	// PaymentSkipped is a payment made by a previous run
+	PaymentSkipped = "skipped"
+	// PaymentPending is a payment whose transfer was sent but not mined before the wait gave up, the next run waits
+	// for it again
+	PaymentPending = "pending"
+	// PaymentFailed is a payment whose transfer could not be sent or reverted, the next run sends it again
+	PaymentFailed = "failed"
+)
+
+// Payment is a row of a distribution, Row is its line in the distribution file.
+type Payment struct {
+	Row    int            `json:"row"`
+	To     common.Address `json:"to"`
+	Amount *big.Int       `json:"amount"`
+}
+
+// PaymentResult reports what `Distribute` did with a payment.
+type PaymentResult struct {
+	Payment
+	Status string      `json:"status"`
+	TxHash common.Hash `json:"txHash"`
+	Error  string      `json:"error,omitempty"`
+
+	err error
+}
+
+// DistributeOpts configures how `Distribute` sends the transfers.
+type DistributeOpts struct {
+	// Concurrency is the number of transfers in flight at once, they are sent with consecutive nonces and waited
+	// for together. Transfers are sent one at a time when it is below 1 or when the contract has no nonce manager.
+	Concurrency int
+	// Wait is how every transfer is waited for
+	Wait WaitOpts
+}
+
+// ParsePayments reads a distribution file of `address,amount` rows, an optional `address,amount` header and
+// lines starting with `#` are skipped. Amounts are converted with units, or read in base units when it is nil.
+// Every row is checked before any is returned: invalid rows are all logged and the first one is returned as
+// the error, matching the address or amount errors of the row with `errors.Is`.
+func ParsePayments(r io.Reader, units *TokenUnits) ([]Payment, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var (
+		payments []Payment
+		firstErr error
+	)
+
+	seen := map[common.Address]int{}
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidDistribution, err)
+			log.Err(err).Msg("unable to read distribution")
+			return nil, err
+		}
+
+		row, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			continue
+		}
+
+		p, err := parsePayment(row, record, units)
+		if err != nil {
+			err = fmt.Errorf("row %d: %w", row, err)
+			log.Err(err).Msg("invalid payment")
+
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		if prev, ok := seen[p.To]; ok {
+			log.Warn().Msgf("row %d pays %s again, it is already paid by row %d", row, p.To.Hex(), prev)
+		}
+
+		seen[p.To] = row
+		payments = append(payments, p)
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if len(payments) == 0 {
+		err := fmt.Errorf("%w: no payments", ErrInvalidDistribution)
+		log.Err(err).Msg("unable to read distribution")
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func parsePayment(row int, record []string, units *TokenUnits) (Payment, error) {
+	if len(record) != 2 {
+		return Payment{}, fmt.Errorf("%w: expected address,amount, got %d fields", ErrInvalidDistribution, len(record))
+	}
+
+	to, err := parseRecipient(strings.TrimSpace(record[0]))
+	if err != nil {
+		return Payment{}, err
+	}
+
+	input := strings.TrimSpace(record[1])
+
+	var amount *big.Int
+	if units != nil {
+		amount, err = units.Parse(input)
+	} else {
+		amount, err = ParseAmount(input)
+	}
+
+	if err != nil {
+		return Payment{}, err
+	}
+
+	if amount.Sign() == 0 {
+		return Payment{}, &AmountError{Input: input, Err: fmt.Errorf("%w: nothing to pay", ErrInvalidAmount)}
+	}
+
+	return Payment{Row: row, To: to, Amount: amount}, nil
+}
+
+// DistributionState is the progress of a distribution, saved to its file as soon as it changes so a run that
+// crashed or was interrupted resumes without paying anyone twice. A transfer is saved before it is sent, the next
+// run waits for it, or sends it again as is when the node does not know it, so it is never mined along with a
+// new transfer of the same payment.
+type DistributionState struct {
+	ChainID  uint64          `json:"chainId"`
+	From     common.Address  `json:"from"`
+	Payments []*PaymentState `json:"payments"`
+
+	path string
+	mu   sync.Mutex
+	rows map[int]*PaymentState
+}
+
+// PaymentState is the progress of a payment, the transfer paying it is kept signed until it is mined.
+type PaymentState struct {
+	Row    int            `json:"row"`
+	To     common.Address `json:"to"`
+	Amount *big.Int       `json:"amount"`
+	TxHash common.Hash    `json:"txHash"`
+	Raw    hexutil.Bytes  `json:"raw,omitempty"`
+	Paid   bool           `json:"paid"`
+}
+
+// LoadDistributionState reads the state kept in the file, a missing file is the state of a new distribution.
+func LoadDistributionState(path string) (*DistributionState, error) {
+	s := &DistributionState{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		log.Err(err).Msg("unable to read distribution state")
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		err = fmt.Errorf("%w: state file %s: %v", ErrInvalidDistribution, path, err)
+		log.Err(err).Msg("unable to read distribution state")
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// bind ties the state to the account and chain paying, and to the payments, which must not have changed since an
+// earlier run.
+func (s *DistributionState) bind(chainID uint64, from common.Address, payments []Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ChainID != 0 && (s.ChainID != chainID || s.From != from) {
+		return fmt.Errorf("%w: state file %s is kept for %s on chain %d", ErrInvalidDistribution, s.path, s.From.Hex(), s.ChainID)
+	}
+
+	s.ChainID, s.From = chainID, from
+
+	s.rows = map[int]*PaymentState{}
+	for _, ps := range s.Payments {
+		s.rows[ps.Row] = ps
+	}
+
+	for _, p := range payments {
+		ps, ok := s.rows[p.Row]
+		if !ok {
+			ps = &PaymentState{Row: p.Row, To: p.To, Amount: p.Amount}
+			s.rows[p.Row] = ps
+			s.Payments = append(s.Payments, ps)
+
+			continue
+		}
+
+		if ps.To != p.To || ps.Amount == nil || ps.Amount.Cmp(p.Amount) != 0 {
+			return fmt.Errorf("%w: row %d changed since the state file %s was written", ErrInvalidDistribution, p.Row, s.path)
+		}
+	}
+
+	return s.save()
+}
+
+// update changes the state of a payment and saves it.
+func (s *DistributionState) update(change func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change()
+
+	return s.save()
+}
+
+// payment returns a copy of the state of the payment on the row.
+func (s *DistributionState) payment(row int) PaymentState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.rows[row]
+}
+
+// save replaces the file at once, a crash while writing leaves the previous state.
+func (s *DistributionState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Err(err).Msg("unable to save distribution state")
+		return err
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		log.Err(err).Msg("unable to save distribution state")
+		return err
+	}
+
+	return nil
+}
+
+// Distribute pays every payment with a token transfer from the owner address, the total still to pay is checked
+// against the owner balance before anything is sent. Progress is kept in the state, so payments made by an earlier
+// run are skipped and the transfers it sent are waited for instead of being sent again. The results are in the order
+// of the payments, along with an error when any of them was not paid.
+func (c *Contract) Distribute(instance IGoldcoin, payments []Payment, state *DistributionState, opts DistributeOpts) ([]PaymentResult, error) {
+	return c.DistributeContext(context.Background(), instance, payments, state, opts)
+}
+
+// DistributeContext is like `Distribute` but every node call is bound to the given context.
+func (c *Contract) DistributeContext(ctx context.Context, instance IGoldcoin, payments []Payment, state *DistributionState, opts DistributeOpts) ([]PaymentResult, error) {
+	if c.Signer == nil {
+		log.Err(ErrNoSigner).Msg("unable to distribute tokens")
+		return nil, ErrNoSigner
+	}
+
+	chainID, err := c.sendingChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from := c.Signer.Address()
+	if err := state.bind(chainID.Uint64(), from, payments); err != nil {
+		log.Err(err).Msg("unable to resume distribution")
+		return nil, err
+	}
+
+	// transfers sent by an earlier run are waited for, their amount may already have left the balance
+	total := new(big.Int)
+	for _, p := range payments {
+		if ps := state.payment(p.Row); ps.TxHash == (common.Hash{}) {
+			total.Add(total, p.Amount)
+		}
+	}
+
+	if err := checkBalance(ctx, instance, from, total); err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 || c.Nonces == nil {
+		concurrency = 1
+	}
+
+	d := &distributor{c: c, instance: instance, state: state, wait: opts.Wait}
+
+	results := make([]PaymentResult, len(payments))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range jobs {
+				results[j] = d.pay(ctx, payments[j])
+			}
+		}()
+	}
+
+	for j := range payments {
+		jobs <- j
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	var (
+		unpaid   int
+		firstErr error
+	)
+
+	for _, r := range results {
+		if r.err != nil {
+			unpaid++
+
+			if firstErr == nil {
+				firstErr = r.err
+			}
+		}
+	}
+
+	if unpaid > 0 {
+		err := fmt.Errorf("%d of %d payments not made, run again to retry them: %w", unpaid, len(payments), firstErr)
+		log.Err(err).Msg("distribution incomplete")
+		return results, err
+	}
+
+	return results, nil
+}
+
+// distributor holds the state of a single `Distribute` run.
+type distributor struct {
+	c        *Contract
+	instance IGoldcoin
+	state    *DistributionState
+	wait     WaitOpts
+}
+
+// pay makes a single payment, resuming the transfer sent by an earlier run when there is one.
+func (d *distributor) pay(ctx context.Context, p Payment) PaymentResult {
+	res := PaymentResult{Payment: p}
+
+	ps := d.state.payment(p.Row)
+	if ps.Paid {
+		res.Status, res.TxHash = PaymentSkipped, ps.TxHash
+		log.Info().Msgf("row %d skipped", p.Row)
+
+		return res
+	}
+
+	txHash, err := d.resume(ctx, ps)
+	if err == nil && txHash != (common.Hash{}) {
+		if _, err = d.c.WaitMinedContext(ctx, txHash, d.wait); errors.Is(err, ErrTxReverted) {
+			log.Warn().Msgf("transfer %s of row %d reverted, sending it again", txHash.Hex(), p.Row)
+			txHash, err = common.Hash{}, nil
+		}
+	}
+
+	if err == nil && txHash == (common.Hash{}) {
+		if txHash, err = d.send(ctx, p); err == nil {
+			_, err = d.c.WaitMinedContext(ctx, txHash, d.wait)
+		}
+	}
+
+	res.TxHash = txHash
+
+	if err == nil {
+		err = d.state.update(func() { d.state.rows[p.Row].Paid = true })
+	}
+
+	switch {
+	case err == nil:
+		res.Status = PaymentPaid
+	case res.TxHash != (common.Hash{}) && !errors.Is(err, ErrTxReverted):
+		res.Status = PaymentPending
+	default:
+		res.Status = PaymentFailed
+	}
+
+	if err != nil {
+		err = fmt.Errorf("row %d: %w", p.Row, err)
+		res.Error, res.err = err.Error(), err
+		log.Err(err).Msgf("payment %s", res.Status)
+	} else {
+		log.Info().Msgf("row %d paid by %s", p.Row, txHash.Hex())
+	}
+
+	return res
+}
+
+// resume returns the hash of the transfer sent by an earlier run to wait for, the zero hash means a new transfer
+// has to be sent: none was sent, or its nonce was taken by another transaction.
+func (d *distributor) resume(ctx context.Context, ps PaymentState) (common.Hash, error) {
+	if ps.TxHash == (common.Hash{}) {
+		return common.Hash{}, nil
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(ps.Raw); err != nil {
+		return common.Hash{}, fmt.Errorf("%w: transfer of row %d: %v", ErrInvalidDistribution, ps.Row, err)
+	}
+
+	_, _, err := d.c.Client.TransactionByHash(ctx, ps.TxHash)
+	if errors.Is(err, ethereum.NotFound) {
+		// the run crashed before sending it, or it was dropped, sending it as is keeps its nonce so it can not be
+		// mined along with a new transfer
+		log.Warn().Msgf("transfer %s of row %d is unknown to the node, sending it again", ps.TxHash.Hex(), ps.Row)
+
+		err = d.c.Client.SendTransaction(ctx, tx)
+		if IsNonceError(err) {
+			// its nonce was used by another transaction, unless it was this transfer mined meanwhile
+			if _, _, err := d.c.Client.TransactionByHash(ctx, ps.TxHash); errors.Is(err, ethereum.NotFound) {
+				return common.Hash{}, nil
+			} else if err != nil {
+				return common.Hash{}, err
+			}
+		} else if err != nil {
+			return common.Hash{}, err
+		}
+	} else if err != nil {
+		log.Err(err).Msg("unable to get transaction")
+		return common.Hash{}, err
+	}
+
+	return ps.TxHash, nil
+}
+
+// send signs the transfer of the payment and saves it before sending it.
+func (d *distributor) send(ctx context.Context, p Payment) (common.Hash, error) {
+	auth, release, err := d.c.getTxSigner(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer release()
+
+	auth.NoSend = true
+
+	tx, err := d.instance.Transfer(auth, p.To, p.Amount)
+	if err != nil {
+		log.Err(err).Msg("unable to make transaction")
+		return common.Hash{}, err
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	ps := d.state.rows[p.Row]
+	if err := d.state.update(func() { ps.TxHash, ps.Raw = tx.Hash(), raw }); err != nil {
+		return common.Hash{}, err
+	}
+
+	if err := d.c.backend().SendTransaction(ctx, tx); err != nil {
+		log.Err(err).Msg("unable to send transaction")
+
+		// the node did not take it, the next run sends a new transfer instead
+		if _, _, getErr := d.c.Client.TransactionByHash(ctx, tx.Hash()); errors.Is(getErr, ethereum.NotFound) {
+			if err := d.state.update(func() { ps.TxHash, ps.Raw = common.Hash{}, nil }); err != nil {
+				return common.Hash{}, err
+			}
+		}
+
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
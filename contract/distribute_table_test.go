@@ -0,0 +1,283 @@
+package contract_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// A test function that tests reading a distribution file.
+func (ts *TableSuite) TestParsePayments() {
+	units := &contract.TokenUnits{Decimals: 2, Symbol: "GLD"}
+
+	ts.Run("Reads rows with a header and comments", func() {
+		payments, err := contract.ParsePayments(strings.NewReader(
+			"address,amount\n# team\n"+holderAddr.Hex()+",12.5\n\n"+spenderAddr.Hex()+", 3 GLD\n"), units)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []contract.Payment{
+			{Row: 3, To: holderAddr, Amount: big.NewInt(1250)},
+			{Row: 5, To: spenderAddr, Amount: big.NewInt(300)},
+		}, payments)
+	})
+
+	ts.Run("Reads base units without token units", func() {
+		payments, err := contract.ParsePayments(strings.NewReader(holderAddr.Hex()+",1250\n"), nil)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []contract.Payment{{Row: 1, To: holderAddr, Amount: big.NewInt(1250)}}, payments)
+	})
+
+	subtests := []struct {
+		name    string
+		csv     string
+		wantErr error
+	}{
+		{name: "Invalid address", csv: holderAddr.Hex() + ",1\n0x1234,1\n", wantErr: contract.ErrInvalidAddress},
+		{name: "Zero address", csv: common.Address{}.Hex() + ",1\n", wantErr: contract.ErrZeroAddress},
+		{name: "Invalid amount", csv: holderAddr.Hex() + ",ten\n", wantErr: contract.ErrInvalidAmount},
+		{name: "Negative amount", csv: holderAddr.Hex() + ",-1\n", wantErr: contract.ErrNegativeAmount},
+		{name: "Zero amount", csv: holderAddr.Hex() + ",0\n", wantErr: contract.ErrInvalidAmount},
+		{name: "Missing amount", csv: holderAddr.Hex() + "\n", wantErr: contract.ErrInvalidDistribution},
+		{name: "No payments", csv: "address,amount\n", wantErr: contract.ErrInvalidDistribution},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			_, err := contract.ParsePayments(strings.NewReader(tt.csv), units)
+			assert.ErrorIs(ts.T(), err, tt.wantErr)
+		})
+	}
+
+	ts.Run("Reports the first invalid row", func() {
+		_, err := contract.ParsePayments(strings.NewReader(holderAddr.Hex()+",1\n0x1234,1\n"+holderAddr.Hex()+",x\n"), units)
+		assert.ErrorContains(ts.T(), err, "row 2")
+	})
+}
+
+// A test function that tests distributing tokens and resuming a distribution.
+func (ts *TableSuite) TestDistribute() {
+	other := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	payments := []contract.Payment{
+		{Row: 2, To: holderAddr, Amount: big.NewInt(10)},
+		{Row: 3, To: spenderAddr, Amount: big.NewInt(20)},
+		{Row: 4, To: other, Amount: big.NewInt(30)},
+	}
+
+	token := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	wait := contract.WaitOpts{PollInterval: time.Millisecond, Timeout: time.Second}
+
+	// node mimics a node on chain 38 the transactions are sent to, fail rejects the transactions it returns an error for
+	type node struct {
+		mu   sync.Mutex
+		sent []*types.Transaction
+		fail func(tx *types.Transaction) error
+	}
+
+	find := func(n *node, hash common.Hash) *types.Transaction {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		for _, tx := range n.sent {
+			if tx.Hash() == hash {
+				return tx
+			}
+		}
+
+		return nil
+	}
+
+	newMock := func(chainID int64, n *node) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(chainID), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{}, nil).AnyTimes()
+		m.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(1000), nil).AnyTimes()
+		m.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		// the transactions sent are counted in the pending nonce, from nonce 5 on
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).DoAndReturn(func(context.Context, common.Address) (uint64, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			return uint64(5 + len(n.sent)), nil
+		}).AnyTimes()
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if n.fail != nil {
+				if err := n.fail(tx); err != nil {
+					return err
+				}
+			}
+
+			n.sent = append(n.sent, tx)
+
+			return nil
+		}).AnyTimes()
+		m.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+			if tx := find(n, hash); tx != nil {
+				return tx, false, nil
+			}
+
+			return nil, false, ethereum.NotFound
+		}).AnyTimes()
+		m.EXPECT().TransactionReceipt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+			if find(n, hash) == nil {
+				return nil, ethereum.NotFound
+			}
+
+			return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}, nil
+		}).AnyTimes()
+
+		return m
+	}
+
+	// transfer signs the transfers the way the goldcoin binding does when told not to send them
+	transfer := func(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+		assert.True(ts.T(), opts.NoSend)
+
+		data, err := contract.GoldcoinArtifact().ABI.Pack("transfer", to, amount)
+		ts.Require().NoError(err)
+
+		return opts.Signer(opts.From, types.NewTx(&types.LegacyTx{
+			Nonce: opts.Nonce.Uint64(), To: &token, Gas: 60000, GasPrice: opts.GasPrice, Data: data,
+		}))
+	}
+
+	newToken := func(balance int64, transfers int) *contract.MockIGoldcoin {
+		m := contract.NewMockIGoldcoin(gomock.NewController(ts.T()))
+		m.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(big.NewInt(balance), nil).AnyTimes()
+		m.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(transfer).Times(transfers)
+
+		return m
+	}
+
+	statuses := func(results []contract.PaymentResult) []string {
+		var s []string
+		for _, r := range results {
+			s = append(s, r.Status)
+		}
+
+		return s
+	}
+
+	dir := ts.T().TempDir()
+
+	ts.Run("Pays every row with consecutive nonces and skips them on the next run", func() {
+		path := filepath.Join(dir, "paid.state.json")
+		n := &node{}
+		c := contract.NewContract(newMock(38, n), contract.WithSigner(testSigner))
+
+		state, err := contract.LoadDistributionState(path)
+		ts.Require().NoError(err)
+
+		results, err := c.Distribute(newToken(60, 3), payments, state, contract.DistributeOpts{Concurrency: 2, Wait: wait})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{contract.PaymentPaid, contract.PaymentPaid, contract.PaymentPaid}, statuses(results))
+
+		nonces := map[uint64]bool{}
+		for _, tx := range n.sent {
+			nonces[tx.Nonce()] = true
+		}
+
+		assert.Equal(ts.T(), map[uint64]bool{5: true, 6: true, 7: true}, nonces)
+
+		// the balance spent by the first run is not needed again
+		state, err = contract.LoadDistributionState(path)
+		ts.Require().NoError(err)
+
+		results, err = c.Distribute(newToken(0, 0), payments, state, contract.DistributeOpts{Concurrency: 2, Wait: wait})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{contract.PaymentSkipped, contract.PaymentSkipped, contract.PaymentSkipped}, statuses(results))
+		assert.Len(ts.T(), n.sent, 3)
+
+		_, err = contract.NewContract(newMock(39, &node{}), contract.WithSigner(testSigner)).
+			Distribute(newToken(60, 0), payments, state, contract.DistributeOpts{Wait: wait})
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidDistribution)
+	})
+
+	ts.Run("Sends a transfer saved before a crash as is", func() {
+		path := filepath.Join(dir, "crashed.state.json")
+
+		// the run crashed once the transfer of the first row was saved, before it was sent
+		data, err := contract.GoldcoinArtifact().ABI.Pack("transfer", holderAddr, big.NewInt(10))
+		ts.Require().NoError(err)
+		saved, err := testSigner.SignTx(types.NewTx(&types.LegacyTx{Nonce: 5, To: &token, Gas: 60000, GasPrice: big.NewInt(1000), Data: data}), big.NewInt(38))
+		ts.Require().NoError(err)
+		raw, err := saved.MarshalBinary()
+		ts.Require().NoError(err)
+
+		crashed, err := json.Marshal(&contract.DistributionState{ChainID: 38, From: testAddr, Payments: []*contract.PaymentState{
+			{Row: 2, To: holderAddr, Amount: big.NewInt(10), TxHash: saved.Hash(), Raw: raw},
+		}})
+		ts.Require().NoError(err)
+		ts.Require().NoError(os.WriteFile(path, crashed, 0o600))
+
+		n := &node{}
+		c := contract.NewContract(newMock(38, n), contract.WithSigner(testSigner))
+
+		state, err := contract.LoadDistributionState(path)
+		ts.Require().NoError(err)
+
+		// only the rows without a saved transfer are paid by new ones
+		results, err := c.Distribute(newToken(50, 2), payments, state, contract.DistributeOpts{Wait: wait})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{contract.PaymentPaid, contract.PaymentPaid, contract.PaymentPaid}, statuses(results))
+		assert.Equal(ts.T(), saved.Hash(), results[0].TxHash)
+		ts.Require().Len(n.sent, 3)
+		assert.Equal(ts.T(), saved.Hash(), n.sent[0].Hash())
+		assert.Equal(ts.T(), []uint64{5, 6, 7}, []uint64{n.sent[0].Nonce(), n.sent[1].Nonce(), n.sent[2].Nonce()})
+	})
+
+	ts.Run("Sends rejected transfers again on the next run", func() {
+		path := filepath.Join(dir, "rejected.state.json")
+		n := &node{fail: func(tx *types.Transaction) error {
+			if tx.Nonce() == 6 {
+				return errors.New("insufficient funds for gas * price + value")
+			}
+
+			return nil
+		}}
+		c := contract.NewContract(newMock(38, n), contract.WithSigner(testSigner))
+
+		state, err := contract.LoadDistributionState(path)
+		ts.Require().NoError(err)
+
+		results, err := c.Distribute(newToken(30, 2), payments[:2], state, contract.DistributeOpts{Wait: wait})
+		assert.Error(ts.T(), err)
+		assert.Equal(ts.T(), []string{contract.PaymentPaid, contract.PaymentFailed}, statuses(results))
+		assert.Equal(ts.T(), common.Hash{}, results[1].TxHash)
+
+		state, err = contract.LoadDistributionState(path)
+		ts.Require().NoError(err)
+
+		n.fail = nil
+
+		results, err = c.Distribute(newToken(20, 1), payments[:2], state, contract.DistributeOpts{Wait: wait})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), []string{contract.PaymentSkipped, contract.PaymentPaid}, statuses(results))
+	})
+
+	ts.Run("Checks the balance before sending anything", func() {
+		c := contract.NewContract(newMock(38, &node{}), contract.WithSigner(testSigner))
+
+		state, err := contract.LoadDistributionState(filepath.Join(dir, "poor.state.json"))
+		ts.Require().NoError(err)
+
+		_, err = c.Distribute(newToken(59, 0), payments, state, contract.DistributeOpts{Wait: wait})
+		assert.ErrorIs(ts.T(), err, contract.ErrInsufficientBalance)
+	})
+}
@@ -0,0 +1,201 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Approve allows the spender to transfer up to amount tokens from the owner address, replacing any previous allowance
+func (c *Contract) Approve(instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	return c.ApproveContext(context.Background(), instance, spender, amountStr)
+}
+
+// ApproveContext is like `Approve` but the transaction is built and sent with the given context.
+func (c *Contract) ApproveContext(ctx context.Context, instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	spenderAddr, amount, err := parseSpend(spender, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tx, err := instance.Approve(auth, spenderAddr, amount)
+	if err != nil {
+		log.Err(err).Msg("unable to approve spender")
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Allowance returns the amount of tokens the spender may still transfer from the owner, the owner address is
+// used when owner is empty
+func (c *Contract) Allowance(instance IGoldcoin, owner string, spender string) (*big.Int, error) {
+	return c.AllowanceContext(context.Background(), instance, owner, spender)
+}
+
+// AllowanceContext is like `Allowance` but the allowance is read with the given context.
+func (c *Contract) AllowanceContext(ctx context.Context, instance IGoldcoin, owner string, spender string) (*big.Int, error) {
+	ownerAddr, err := c.accountOrOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	spenderAddr, err := ParseAddress(spender)
+	if err != nil {
+		log.Err(err).Msg("invalid spender address")
+		return nil, err
+	}
+
+	allowance, err := instance.Allowance(&bind.CallOpts{Context: ctx}, ownerAddr, spenderAddr)
+	if err != nil {
+		log.Err(err).Msg("unable to get allowance")
+		return nil, err
+	}
+
+	return allowance, nil
+}
+
+// TransferFrom transfers tokens from the from address to the reciever address out of the allowance the from
+// address gave to the owner address
+func (c *Contract) TransferFrom(instance IGoldcoin, from string, recieverAddr string, amountStr string) (*types.Transaction, error) {
+	return c.TransferFromContext(context.Background(), instance, from, recieverAddr, amountStr)
+}
+
+// TransferFromContext is like `TransferFrom` but the transaction is built and sent with the given context.
+func (c *Contract) TransferFromContext(ctx context.Context, instance IGoldcoin, from string, recieverAddr string, amountStr string) (*types.Transaction, error) {
+	fromAddr, err := parseRecipient(from)
+	if err != nil {
+		log.Err(err).Msg("invalid sender address")
+		return nil, err
+	}
+
+	to, amount, err := parseSpend(recieverAddr, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := checkAllowance(ctx, instance, fromAddr, auth.From, amount); err != nil {
+		return nil, err
+	}
+
+	if err := checkBalance(ctx, instance, fromAddr, amount); err != nil {
+		return nil, err
+	}
+
+	tx, err := instance.TransferFrom(auth, fromAddr, to, amount)
+	if err != nil {
+		log.Err(err).Msg("unable to make transaction")
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// IncreaseAllowance adds amount to the tokens the spender may transfer from the owner address
+func (c *Contract) IncreaseAllowance(instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	return c.IncreaseAllowanceContext(context.Background(), instance, spender, amountStr)
+}
+
+// IncreaseAllowanceContext is like `IncreaseAllowance` but the transaction is built and sent with the given context.
+func (c *Contract) IncreaseAllowanceContext(ctx context.Context, instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	spenderAddr, amount, err := parseSpend(spender, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tx, err := instance.IncreaseAllowance(auth, spenderAddr, amount)
+	if err != nil {
+		log.Err(err).Msg("unable to increase allowance")
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// DecreaseAllowance removes amount from the tokens the spender may transfer from the owner address, the
+// allowance cannot go below zero
+func (c *Contract) DecreaseAllowance(instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	return c.DecreaseAllowanceContext(context.Background(), instance, spender, amountStr)
+}
+
+// DecreaseAllowanceContext is like `DecreaseAllowance` but the transaction is built and sent with the given context.
+func (c *Contract) DecreaseAllowanceContext(ctx context.Context, instance IGoldcoin, spender string, amountStr string) (*types.Transaction, error) {
+	spenderAddr, amount, err := parseSpend(spender, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, release, err := c.getTxSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := checkAllowance(ctx, instance, auth.From, spenderAddr, amount); err != nil {
+		return nil, err
+	}
+
+	tx, err := instance.DecreaseAllowance(auth, spenderAddr, amount)
+	if err != nil {
+		log.Err(err).Msg("unable to decrease allowance")
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// parseSpend parses the non zero address tokens are sent or approved to along with the amount.
+func parseSpend(recipient string, amountStr string) (common.Address, *big.Int, error) {
+	addr, err := parseRecipient(recipient)
+	if err != nil {
+		log.Err(err).Msg("invalid reciever address")
+		return common.Address{}, nil, err
+	}
+
+	amount, err := ParseAmount(amountStr)
+	if err != nil {
+		log.Err(err).Msg("invalid amount")
+		return common.Address{}, nil, err
+	}
+
+	return addr, amount, nil
+}
+
+// checkAllowance fails with `*InsufficientAllowanceError` when the owner allows the spender less than amount.
+func checkAllowance(ctx context.Context, instance IGoldcoin, owner, spender common.Address, amount *big.Int) error {
+	allowance, err := instance.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+	if err != nil {
+		log.Err(err).Msg("unable to get allowance")
+		return err
+	}
+
+	if allowance.Cmp(amount) < 0 {
+		err := &InsufficientAllowanceError{Owner: owner, Spender: spender, Allowance: allowance, Amount: amount}
+		log.Err(err).Msg("unable to make transaction")
+		return err
+	}
+
+	return nil
+}
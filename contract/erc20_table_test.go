@@ -0,0 +1,219 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+var (
+	spenderAddr = common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d")
+	holderAddr  = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+)
+
+// expectTxOpts sets the node calls made when building the options of a transaction
+func expectTxOpts(m *contract.MockIBlockchain) {
+	m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+	m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+	m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+	m.EXPECT().PendingNonceAt(context.Background(), testAddr).Return(uint64(1), nil)
+}
+
+// A test function that tests the allowance based transactions of the contract module.
+func (ts *TableSuite) TestAllowanceTransactions() {
+	subtests := []struct {
+		name    string
+		prepare func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin)
+		send    func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error)
+		wantErr error
+	}{
+		{
+			name: "Approve spender",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Approve(gomock.Any(), spenderAddr, big.NewInt(100)).Return(&types.Transaction{}, nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.Approve(mg, spenderAddr.Hex(), "100")
+			},
+		},
+		{
+			name: "Error approve zero address",
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.Approve(mg, common.Address{}.Hex(), "100")
+			},
+			wantErr: contract.ErrZeroAddress,
+		},
+		{
+			name: "Error approve negative amount",
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.Approve(mg, spenderAddr.Hex(), "-1")
+			},
+			wantErr: contract.ErrNegativeAmount,
+		},
+		{
+			name: "Transfer from holder",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Allowance(gomock.Any(), holderAddr, testAddr).Return(big.NewInt(100), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), holderAddr).Return(big.NewInt(100), nil)
+				mg.EXPECT().TransferFrom(gomock.Any(), holderAddr, spenderAddr, big.NewInt(100)).Return(&types.Transaction{}, nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.TransferFrom(mg, holderAddr.Hex(), spenderAddr.Hex(), "100")
+			},
+		},
+		{
+			name: "Error transfer from above allowance",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Allowance(gomock.Any(), holderAddr, testAddr).Return(big.NewInt(99), nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.TransferFrom(mg, holderAddr.Hex(), spenderAddr.Hex(), "100")
+			},
+			wantErr: contract.ErrInsufficientAllowance,
+		},
+		{
+			name: "Error transfer from above balance",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Allowance(gomock.Any(), holderAddr, testAddr).Return(big.NewInt(100), nil)
+				mg.EXPECT().BalanceOf(gomock.Any(), holderAddr).Return(big.NewInt(10), nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.TransferFrom(mg, holderAddr.Hex(), spenderAddr.Hex(), "100")
+			},
+			wantErr: contract.ErrInsufficientBalance,
+		},
+		{
+			name: "Error transfer from invalid address",
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.TransferFrom(mg, "evmos1invalid", spenderAddr.Hex(), "100")
+			},
+			wantErr: contract.ErrInvalidAddress,
+		},
+		{
+			name: "Increase allowance",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().IncreaseAllowance(gomock.Any(), spenderAddr, big.NewInt(50)).Return(&types.Transaction{}, nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.IncreaseAllowance(mg, spenderAddr.Hex(), "50")
+			},
+		},
+		{
+			name: "Decrease allowance",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Allowance(gomock.Any(), testAddr, spenderAddr).Return(big.NewInt(100), nil)
+				mg.EXPECT().DecreaseAllowance(gomock.Any(), spenderAddr, big.NewInt(50)).Return(&types.Transaction{}, nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.DecreaseAllowance(mg, spenderAddr.Hex(), "50")
+			},
+		},
+		{
+			name: "Error decrease allowance below zero",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				expectTxOpts(m)
+				mg.EXPECT().Allowance(gomock.Any(), testAddr, spenderAddr).Return(big.NewInt(10), nil)
+			},
+			send: func(c *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return c.DecreaseAllowance(mg, spenderAddr.Hex(), "50")
+			},
+			wantErr: contract.ErrInsufficientAllowance,
+		},
+		{
+			name: "Error approve without signer",
+			send: func(_ *contract.Contract, mg *contract.MockIGoldcoin) (*types.Transaction, error) {
+				return contract.NewContract(ts.ClientMock).Approve(mg, spenderAddr.Hex(), "100")
+			},
+			wantErr: contract.ErrNoSigner,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			if tt.prepare != nil {
+				tt.prepare(ts.ClientMock, ts.GoldcoinMock)
+			}
+
+			tx, err := tt.send(ts.Contract, ts.GoldcoinMock)
+			if tt.wantErr != nil {
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.NotNil(ts.T(), tx)
+		})
+	}
+}
+
+// A test function that tests the `Allowance` function.
+func (ts *TableSuite) TestAllowance() {
+	subtests := []struct {
+		name     string
+		owner    string
+		prepare  func(mg *contract.MockIGoldcoin)
+		noSigner bool
+		wantErr  bool
+	}{
+		{
+			name: "Allowance of the owner address",
+			prepare: func(mg *contract.MockIGoldcoin) {
+				mg.EXPECT().Allowance(gomock.Any(), testAddr, spenderAddr).Return(big.NewInt(100), nil)
+			},
+		},
+		{
+			name:  "Allowance of a given holder",
+			owner: holderAddr.Hex(),
+			prepare: func(mg *contract.MockIGoldcoin) {
+				mg.EXPECT().Allowance(gomock.Any(), holderAddr, spenderAddr).Return(big.NewInt(100), nil)
+			},
+		},
+		{
+			name: "Error Allowance",
+			prepare: func(mg *contract.MockIGoldcoin) {
+				mg.EXPECT().Allowance(gomock.Any(), testAddr, spenderAddr).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Error No Signer",
+			noSigner: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			if tt.prepare != nil {
+				tt.prepare(ts.GoldcoinMock)
+			}
+
+			c := ts.Contract
+			if tt.noSigner {
+				c = contract.NewContract(ts.ClientMock)
+			}
+
+			allowance, err := c.Allowance(ts.GoldcoinMock, tt.owner, spenderAddr.Hex())
+			if tt.wantErr {
+				assert.Error(ts.T(), err)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), big.NewInt(100), allowance)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/gopherine/evmos-conploy/address"
+)
+
+var (
+	// ErrInvalidAddress is matched by every malformed address, including ones failing their checksum
+	ErrInvalidAddress = address.ErrInvalidAddress
+	// ErrZeroAddress is returned when tokens would be sent to the zero address and burnt
+	ErrZeroAddress = errors.New("zero address")
+	// ErrInvalidAmount is returned for amounts that are not a base 10 integer
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrNegativeAmount is returned for amounts below zero
+	ErrNegativeAmount = errors.New("negative amount")
+	// ErrInsufficientBalance is returned when the sender holds less tokens than the amount sent
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrInsufficientAllowance is returned when a spender is allowed less tokens than the amount spent or removed
+	ErrInsufficientAllowance = errors.New("insufficient allowance")
+	// ErrInvalidArgument is matched by every constructor or method argument that can not be converted to its ABI type
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrUnknownMethod is returned when a method is called that is not in the ABI of the contract
+	ErrUnknownMethod = errors.New("unknown method")
+	// ErrNoABI is returned when a contract is called whose ABI is neither recorded in the registry nor given
+	ErrNoABI = errors.New("no ABI known")
+	// ErrInvalidTransaction is returned for transaction files that can not be decoded or do not match their signature
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	// ErrWrongSigner is returned when a transaction is signed with another account than the one it was built for, or
+	// replaced with another account than the one that sent it
+	ErrWrongSigner = errors.New("wrong signer")
+	// ErrNotPending is returned when replacing a transaction that is already mined
+	ErrNotPending = errors.New("transaction not pending")
+	// ErrInvalidDistribution is returned for distribution files that can not be read, and for state files kept for
+	// another account, chain or distribution
+	ErrInvalidDistribution = errors.New("invalid distribution")
+)
+
+// AddressError describes an address input that was rejected, it matches `ErrInvalidAddress` with `errors.Is`
+// along with the more specific cause in `Err`.
+type AddressError struct {
+	Input string
+	Err   error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("address %q: %v", e.Input, e.Err)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports every address error as an invalid address.
+func (e *AddressError) Is(target error) bool {
+	return target == ErrInvalidAddress
+}
+
+// AmountError describes an amount input that was rejected, `Err` is either `ErrInvalidAmount` or `ErrNegativeAmount`.
+type AmountError struct {
+	Input string
+	Err   error
+}
+
+func (e *AmountError) Error() string {
+	return fmt.Sprintf("amount %q: %v", e.Input, e.Err)
+}
+
+func (e *AmountError) Unwrap() error {
+	return e.Err
+}
+
+// InsufficientBalanceError describes a transfer above the balance of the sender, it matches
+// `ErrInsufficientBalance` with `errors.Is`.
+type InsufficientBalanceError struct {
+	Account common.Address
+	Balance *big.Int
+	Amount  *big.Int
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("%s: %s holds %s, %s needed", ErrInsufficientBalance, e.Account.Hex(), e.Balance, e.Amount)
+}
+
+func (e *InsufficientBalanceError) Unwrap() error {
+	return ErrInsufficientBalance
+}
+
+// InsufficientAllowanceError describes a transfer or allowance decrease above what the owner allows the spender,
+// it matches `ErrInsufficientAllowance` with `errors.Is`.
+type InsufficientAllowanceError struct {
+	Owner     common.Address
+	Spender   common.Address
+	Allowance *big.Int
+	Amount    *big.Int
+}
+
+func (e *InsufficientAllowanceError) Error() string {
+	return fmt.Sprintf("%s: %s allows %s %s, %s needed", ErrInsufficientAllowance, e.Owner.Hex(), e.Spender.Hex(), e.Allowance, e.Amount)
+}
+
+func (e *InsufficientAllowanceError) Unwrap() error {
+	return ErrInsufficientAllowance
+}
+
+// ParseAddress parses a 0x hex or evmos bech32 address, failures are returned as `*AddressError`.
+func ParseAddress(s string) (common.Address, error) {
+	addr, err := address.Parse(s)
+	if err != nil {
+		return common.Address{}, &AddressError{Input: s, Err: err}
+	}
+
+	return addr, nil
+}
+
+// ParseAmount parses a base 10 token amount in base units, failures are returned as `*AmountError`.
+func ParseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, &AmountError{Input: s, Err: ErrInvalidAmount}
+	}
+
+	if amount.Sign() < 0 {
+		return nil, &AmountError{Input: s, Err: ErrNegativeAmount}
+	}
+
+	return amount, nil
+}
+
+// ArgumentError describes an argument that was rejected, it matches `ErrInvalidArgument` with `errors.Is`.
+type ArgumentError struct {
+	// Name is the ABI name of the argument, or its position when unnamed
+	Name  string
+	Type  string
+	Input string
+	Err   error
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("argument %s (%s) %q: %v", e.Name, e.Type, e.Input, e.Err)
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports every argument error as an invalid argument.
+func (e *ArgumentError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
@@ -0,0 +1,142 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+// baseFeeMultiplier is the headroom given on top of the latest base fee when computing the fee cap,
+// doubling it keeps the transaction valid for several consecutive full blocks.
+const baseFeeMultiplier = 2
+
+const (
+	// DefaultGasMultiplier is the safety margin applied to gas estimates when the policy does not set one
+	DefaultGasMultiplier = 1.2
+	// DefaultGasCap is the hard limit on transaction gas when the policy does not set one
+	DefaultGasCap = 10_000_000
+)
+
+// ErrGasCapExceeded is returned when the estimated gas of a call is above the configured hard cap
+var ErrGasCapExceeded = errors.New("estimated gas exceeds gas cap")
+
+// GasPolicy controls how fees and gas limits are set on transactions created by the `Contract`.
+type GasPolicy struct {
+	// Legacy forces legacy (pre EIP-1559) transactions even when the chain reports a base fee
+	Legacy bool
+	// Multiplier scales every gas estimate to leave a safety margin, defaults to `DefaultGasMultiplier`
+	Multiplier float64
+	// Cap is the hard gas limit of a single transaction, defaults to `DefaultGasCap`
+	Cap uint64
+}
+
+// gasLimit applies the multiplier and cap of the policy to a gas estimate. Estimates above the cap fail
+// as the transaction would run out of gas, the margin on top of the estimate is clamped to the cap.
+func (p GasPolicy) gasLimit(estimate uint64) (uint64, error) {
+	multiplier, limit := p.Multiplier, p.Cap
+	if multiplier <= 0 {
+		multiplier = DefaultGasMultiplier
+	}
+
+	if limit == 0 {
+		limit = DefaultGasCap
+	}
+
+	if estimate > limit {
+		return 0, fmt.Errorf("%w: %d > %d", ErrGasCapExceeded, estimate, limit)
+	}
+
+	gas := uint64(float64(estimate) * multiplier)
+	if gas > limit {
+		gas = limit
+	}
+
+	return gas, nil
+}
+
+// estimatingBackend is handed to bound contracts instead of the raw client. Bound contracts estimate gas with
+// the packed call (to, data, value) of the method being invoked, the backend applies the gas policy on top. The
+// outcome of every send is reported to the nonce manager, when set.
+type estimatingBackend struct {
+	IBlockchain
+	policy GasPolicy
+	nonces *NonceManager
+}
+
+// EstimateGas estimates the gas of the call and applies the gas policy to it.
+func (b estimatingBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	estimate, err := b.IBlockchain.EstimateGas(ctx, call)
+	if err != nil {
+		log.Err(err).Msg("Unable to estimate gas limit")
+		return 0, err
+	}
+
+	return b.policy.gasLimit(estimate)
+}
+
+// SendTransaction sends the transaction and ends the reservation of its nonce with the outcome.
+func (b estimatingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.IBlockchain.SendTransaction(ctx, tx)
+	if b.nonces == nil {
+		return err
+	}
+
+	if from, senderErr := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); senderErr == nil {
+		b.nonces.Sent(from, tx, err)
+	}
+
+	return err
+}
+
+// backend returns the client bound contracts should be created with.
+func (c *Contract) backend() bind.ContractBackend {
+	return estimatingBackend{IBlockchain: c.Client, policy: c.GasPolicy, nonces: c.Nonces}
+}
+
+// WithGasPolicy sets the gas policy used when building transactions
+func WithGasPolicy(p GasPolicy) Option {
+	return func(c *Contract) {
+		c.GasPolicy = p
+	}
+}
+
+// setFees sets either dynamic fee (EIP-1559) or legacy gas price on the transaction options. Dynamic fees are
+// used whenever the latest header carries a base fee, unless the gas policy forces legacy transactions.
+func (c *Contract) setFees(ctx context.Context, auth *bind.TransactOpts) error {
+	if !c.GasPolicy.Legacy {
+		head, err := c.Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Err(err).Msg("unable to get latest header")
+			return err
+		}
+
+		if head.BaseFee != nil {
+			tipCap, err := c.Client.SuggestGasTipCap(ctx)
+			if err != nil {
+				log.Err(err).Msg("unable to get suggested gas tip cap")
+				return err
+			}
+
+			auth.GasTipCap = tipCap
+			auth.GasFeeCap = new(big.Int).Add(tipCap, new(big.Int).Mul(head.BaseFee, big.NewInt(baseFeeMultiplier)))
+
+			return nil
+		}
+	}
+
+	gasPrice, err := c.Client.SuggestGasPrice(ctx)
+	if err != nil {
+		log.Err(err).Msg("unable to get suggested gas price")
+		return err
+	}
+
+	auth.GasPrice = gasPrice
+
+	return nil
+}
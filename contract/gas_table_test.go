@@ -0,0 +1,194 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// A test function that tests fee selection of transactions built by the contract module.
+func (ts *TableSuite) TestGasPolicy() {
+	subtests := []struct {
+		name      string
+		policy    contract.GasPolicy
+		prepare   func(m *contract.MockIBlockchain)
+		gasPrice  *big.Int
+		gasTipCap *big.Int
+		gasFeeCap *big.Int
+		wantErr   bool
+	}{
+		{
+			name: "Dynamic fee transaction when chain has base fee",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{BaseFee: big.NewInt(500)}, nil)
+				m.EXPECT().SuggestGasTipCap(context.Background()).Return(big.NewInt(10), nil)
+				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
+			},
+			gasTipCap: big.NewInt(10),
+			gasFeeCap: big.NewInt(1010),
+		},
+		{
+			name: "Legacy fallback when chain has no base fee",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
+			},
+			gasPrice: big.NewInt(1000),
+		},
+		{
+			name:   "Legacy forced by gas policy",
+			policy: contract.GasPolicy{Legacy: true},
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+				m.EXPECT().PendingNonceAt(context.Background(), gomock.Any()).Return(uint64(1), nil)
+			},
+			gasPrice: big.NewInt(1000),
+		},
+		{
+			name: "Error HeaderByNumber",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(nil, errors.New("header error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error SuggestGasTipCap",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(001), nil)
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{BaseFee: big.NewInt(500)}, nil)
+				m.EXPECT().SuggestGasTipCap(context.Background()).Return(nil, errors.New("tip cap error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			tt.prepare(ts.ClientMock)
+
+			var opts *bind.TransactOpts
+			if !tt.wantErr {
+				ts.GoldcoinMock.EXPECT().BalanceOf(gomock.Any(), testAddr).Return(testBalance, nil)
+				ts.GoldcoinMock.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(auth *bind.TransactOpts, _ common.Address, _ *big.Int) (*types.Transaction, error) {
+						opts = auth
+						return &types.Transaction{}, nil
+					})
+			}
+
+			c := contract.NewContract(ts.ClientMock, contract.WithSigner(testSigner), contract.WithGasPolicy(tt.policy))
+			_, err := c.TransferTokens(ts.GoldcoinMock, "0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d", "100")
+
+			if tt.wantErr {
+				assert.Error(ts.T(), err)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), tt.gasPrice, opts.GasPrice)
+			assert.Equal(ts.T(), tt.gasTipCap, opts.GasTipCap)
+			assert.Equal(ts.T(), tt.gasFeeCap, opts.GasFeeCap)
+		})
+	}
+}
+
+// A test function that tests gas estimation is done with the packed method call and the gas policy applied.
+func (ts *TableSuite) TestGasEstimation() {
+	contractAddr := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	recieverAddr := common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d")
+
+	err := ts.Registry.Put(&registry.Record{
+		ChainID: 6,
+		Name:    contract.GoldcoinName,
+		Address: contractAddr,
+	})
+	ts.Require().NoError(err)
+
+	parsed, err := goldcoin.GoldcoinMetaData.GetAbi()
+	ts.Require().NoError(err)
+	transferData, err := parsed.Pack("transfer", recieverAddr, big.NewInt(100))
+	ts.Require().NoError(err)
+
+	subtests := []struct {
+		name     string
+		policy   contract.GasPolicy
+		estimate uint64
+		wantGas  uint64
+		wantErr  error
+	}{
+		{
+			name:     "Default multiplier applied to estimate",
+			estimate: 50000,
+			wantGas:  60000,
+		},
+		{
+			name:     "Configured multiplier applied to estimate",
+			policy:   contract.GasPolicy{Multiplier: 1.5},
+			estimate: 50000,
+			wantGas:  75000,
+		},
+		{
+			name:     "Margin clamped to gas cap",
+			policy:   contract.GasPolicy{Cap: 55000},
+			estimate: 50000,
+			wantGas:  55000,
+		},
+		{
+			name:     "Error estimate above gas cap",
+			policy:   contract.GasPolicy{Cap: 40000},
+			estimate: 50000,
+			wantErr:  contract.ErrGasCapExceeded,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			c := contract.NewContract(ts.ClientMock, contract.WithSigner(testSigner), contract.WithRegistry(ts.Registry), contract.WithGasPolicy(tt.policy))
+
+			ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(6), nil).Times(2)
+			ts.ClientMock.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{}, nil)
+			ts.ClientMock.EXPECT().SuggestGasPrice(context.Background()).Return(big.NewInt(1000), nil)
+			ts.ClientMock.EXPECT().PendingNonceAt(context.Background(), testAddr).Return(uint64(1), nil)
+			ts.ClientMock.EXPECT().CallContract(context.Background(), gomock.Any(), nil).Return(common.LeftPadBytes(testBalance.Bytes(), 32), nil)
+			ts.ClientMock.EXPECT().PendingCodeAt(context.Background(), contractAddr).Return([]byte{1}, nil)
+			ts.ClientMock.EXPECT().EstimateGas(context.Background(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+					// estimation is made for the transfer call, not the deploy bytecode
+					assert.Equal(ts.T(), &contractAddr, call.To)
+					assert.Equal(ts.T(), transferData, call.Data)
+					return tt.estimate, nil
+				})
+			if tt.wantErr == nil {
+				ts.ClientMock.EXPECT().SendTransaction(context.Background(), gomock.Any()).Return(nil)
+			}
+
+			instance, err := c.Load()
+			ts.Require().NoError(err)
+
+			tx, err := c.TransferTokens(instance, recieverAddr.Hex(), "100")
+			if tt.wantErr != nil {
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), tt.wantGas, tx.Gas())
+		})
+	}
+}
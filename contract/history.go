@@ -0,0 +1,285 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/index"
+)
+
+// DefaultChunkSize is the number of blocks queried at once when the query does not set one, it stays below the
+// block range limit most nodes put on `eth_getLogs`
+const DefaultChunkSize = 2000
+
+// Token event names
+const (
+	EventTransfer = index.EventTransfer
+	EventApproval = index.EventApproval
+)
+
+// Direction selects which side of an event the queried account has to be on.
+type Direction string
+
+const (
+	// DirectionAny matches events where the account is on either side
+	DirectionAny Direction = "any"
+	// DirectionFrom matches events sent by the account, the owner of approvals
+	DirectionFrom Direction = "from"
+	// DirectionTo matches events received by the account, the spender of approvals
+	DirectionTo Direction = "to"
+)
+
+// ErrInvalidBlockRange is returned when the start block of a history query is past its end block
+var ErrInvalidBlockRange = errors.New("invalid block range")
+
+// HistoryQuery selects the token events returned by `History`.
+type HistoryQuery struct {
+	// Account filters events on the address, all events are returned when it is the zero address
+	Account   common.Address
+	Direction Direction
+	// Events lists the event names to return, both transfers and approvals when empty
+	Events []string
+	// FromBlock defaults to the deploy block of the token
+	FromBlock uint64
+	// ToBlock defaults to the latest block
+	ToBlock *uint64
+	// ChunkSize is the number of blocks queried at once, defaults to `DefaultChunkSize`
+	ChunkSize uint64
+}
+
+// Event is a Transfer or Approval event of the token. For approvals `From` is the owner and `To` the spender.
+type Event struct {
+	Name        string         `json:"event"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+	LogIndex    uint           `json:"logIndex"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Value       *big.Int       `json:"value"`
+	// blockHash is kept for the indexer to detect reorgs
+	blockHash common.Hash
+	// removed is set on logs reverted by a reorg that are streamed by subscriptions
+	removed bool
+}
+
+// History streams the token events selected by the query to fn in chain order. The block range is queried in
+// chunks, fn is called as soon as a chunk is fetched and an error returned by it stops the query.
+func (c *Contract) History(query HistoryQuery, fn func(Event) error) error {
+	return c.HistoryContext(context.Background(), query, fn)
+}
+
+// HistoryContext is like `History` but every node call is made with the given context.
+func (c *Contract) HistoryContext(ctx context.Context, query HistoryQuery, fn func(Event) error) error {
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return err
+	}
+
+	filterer, err := goldcoin.NewGoldcoinFilterer(rec.Address, c.Client)
+	if err != nil {
+		log.Err(err).Msg("unable to bind goldcoin filterer")
+		return err
+	}
+
+	start, chunk := query.FromBlock, query.ChunkSize
+	if start == 0 {
+		start = rec.BlockNumber
+	}
+
+	if chunk == 0 {
+		chunk = DefaultChunkSize
+	}
+
+	var end uint64
+	if query.ToBlock != nil {
+		end = *query.ToBlock
+	} else {
+		head, err := c.Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Err(err).Msg("unable to get latest header")
+			return err
+		}
+
+		end = head.Number.Uint64()
+	}
+
+	if start > end {
+		return fmt.Errorf("%w: %d > %d", ErrInvalidBlockRange, start, end)
+	}
+
+	for from := start; from <= end; from += chunk {
+		to := from + chunk - 1
+		if to > end || to < from {
+			to = end
+		}
+
+		events, err := fetchEvents(ctx, filterer, query.filters(), from, to)
+		if err != nil {
+			log.Err(err).Msgf("unable to get events of blocks %d-%d", from, to)
+			return err
+		}
+
+		for _, ev := range events {
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+
+		if to == end {
+			break
+		}
+	}
+
+	return nil
+}
+
+// filters returns the event filters covering the query, topics of a single filter are and-ed so events on either
+// side of the account need one filter per side.
+func (q HistoryQuery) filters() []eventFilter {
+	switch {
+	case q.Account == (common.Address{}):
+		return []eventFilter{{events: q.Events}}
+	case q.Direction == DirectionFrom:
+		return []eventFilter{{from: []common.Address{q.Account}, events: q.Events}}
+	case q.Direction == DirectionTo:
+		return []eventFilter{{to: []common.Address{q.Account}, events: q.Events}}
+	default:
+		return []eventFilter{
+			{from: []common.Address{q.Account}, events: q.Events},
+			{to: []common.Address{q.Account}, events: q.Events},
+		}
+	}
+}
+
+// eventFilter selects token events by the addresses on each side, nil matches any address. For approvals `from`
+// matches the owner and `to` the spender.
+type eventFilter struct {
+	from   []common.Address
+	to     []common.Address
+	events []string
+}
+
+// wants reports whether the filter matches events with the given name, all events when no names are set.
+func (f eventFilter) wants(name string) bool {
+	if len(f.events) == 0 {
+		return true
+	}
+
+	for _, n := range f.events {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchEvents returns the events matched by any of the filters in the block range, sorted in chain order. Events
+// matched by several filters, like self transfers, are kept once.
+func fetchEvents(ctx context.Context, filterer *goldcoin.GoldcoinFilterer, filters []eventFilter, from, to uint64) ([]Event, error) {
+	opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+
+	seen := make(map[eventKey]bool)
+	var events []Event
+
+	add := func(ev Event) {
+		if !seen[ev.key()] {
+			seen[ev.key()] = true
+			events = append(events, ev)
+		}
+	}
+
+	for _, f := range filters {
+		if f.wants(EventTransfer) {
+			it, err := filterer.FilterTransfer(opts, f.from, f.to)
+			if err != nil {
+				return nil, err
+			}
+
+			for it.Next() {
+				add(transferEvent(it.Event))
+			}
+
+			err = it.Error()
+			it.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if f.wants(EventApproval) {
+			it, err := filterer.FilterApproval(opts, f.from, f.to)
+			if err != nil {
+				return nil, err
+			}
+
+			for it.Next() {
+				add(approvalEvent(it.Event))
+			}
+
+			err = it.Error()
+			it.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+
+		return events[i].LogIndex < events[j].LogIndex
+	})
+
+	return events, nil
+}
+
+// eventKey identifies a single event log
+type eventKey struct {
+	txHash   common.Hash
+	logIndex uint
+}
+
+func (e Event) key() eventKey {
+	return eventKey{txHash: e.TxHash, logIndex: e.LogIndex}
+}
+
+// transferEvent converts a decoded Transfer log to an `Event`.
+func transferEvent(t *goldcoin.GoldcoinTransfer) Event {
+	return Event{
+		Name:        EventTransfer,
+		BlockNumber: t.Raw.BlockNumber,
+		TxHash:      t.Raw.TxHash,
+		LogIndex:    t.Raw.Index,
+		From:        t.From,
+		blockHash:   t.Raw.BlockHash,
+		To:          t.To,
+		Value:       t.Value,
+	}
+}
+
+// approvalEvent converts a decoded Approval log to an `Event`.
+func approvalEvent(a *goldcoin.GoldcoinApproval) Event {
+	return Event{
+		Name:        EventApproval,
+		BlockNumber: a.Raw.BlockNumber,
+		TxHash:      a.Raw.TxHash,
+		LogIndex:    a.Raw.Index,
+		From:        a.Owner,
+		blockHash:   a.Raw.BlockHash,
+		To:          a.Spender,
+		Value:       a.Value,
+	}
+}
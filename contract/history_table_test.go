@@ -0,0 +1,213 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+var (
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approvalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+)
+
+// tokenLog builds the log of a Transfer or Approval event
+func tokenLog(topic common.Hash, block uint64, index uint, from, to common.Address, value int64) types.Log {
+	return types.Log{
+		Topics:      []common.Hash{topic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:        common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		BlockNumber: block,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(block*100 + uint64(index))),
+		Index:       index,
+	}
+}
+
+// filterLogs answers `FilterLogs` queries out of the given logs the way a node would
+func filterLogs(logs []types.Log) func(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	return func(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+		var matched []types.Log
+
+		for _, l := range logs {
+			if l.BlockNumber < q.FromBlock.Uint64() || l.BlockNumber > q.ToBlock.Uint64() {
+				continue
+			}
+
+			match := true
+			for i, topics := range q.Topics {
+				if len(topics) == 0 {
+					continue
+				}
+
+				found := false
+				for _, t := range topics {
+					found = found || t == l.Topics[i]
+				}
+
+				match = match && found
+			}
+
+			if match {
+				matched = append(matched, l)
+			}
+		}
+
+		return matched, nil
+	}
+}
+
+// A test function that tests the `History` function.
+func (ts *TableSuite) TestHistory() {
+	contractAddr := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 16, Name: contract.GoldcoinName, Address: contractAddr, BlockNumber: 100}))
+
+	logs := []types.Log{
+		tokenLog(transferTopic, 100, 0, common.Address{}, testAddr, 1000),
+		tokenLog(transferTopic, 103, 1, testAddr, spenderAddr, 10),
+		tokenLog(approvalTopic, 103, 0, testAddr, spenderAddr, 50),
+		tokenLog(transferTopic, 105, 0, testAddr, testAddr, 5),
+		tokenLog(transferTopic, 105, 2, spenderAddr, holderAddr, 1),
+	}
+
+	toBlock := func(n uint64) *uint64 { return &n }
+
+	subtests := []struct {
+		name       string
+		query      contract.HistoryQuery
+		prepare    func(m *contract.MockIBlockchain)
+		wantBlocks []uint64
+		wantNames  []string
+		wantErr    error
+	}{
+		{
+			name:       "All events from deploy block in chunks",
+			query:      contract.HistoryQuery{ToBlock: toBlock(105), ChunkSize: 2},
+			wantBlocks: []uint64{100, 103, 103, 105, 105},
+			wantNames:  []string{"Transfer", "Approval", "Transfer", "Transfer", "Transfer"},
+		},
+		{
+			name:       "Events on either side of account without duplicate self transfer",
+			query:      contract.HistoryQuery{Account: testAddr, ToBlock: toBlock(105)},
+			wantBlocks: []uint64{100, 103, 103, 105},
+		},
+		{
+			name:       "Transfers sent by account",
+			query:      contract.HistoryQuery{Account: testAddr, Direction: contract.DirectionFrom, Events: []string{contract.EventTransfer}, ToBlock: toBlock(105)},
+			wantBlocks: []uint64{103, 105},
+		},
+		{
+			name:       "Transfers recieved by account",
+			query:      contract.HistoryQuery{Account: spenderAddr, Direction: contract.DirectionTo, Events: []string{contract.EventTransfer}, ToBlock: toBlock(105)},
+			wantBlocks: []uint64{103},
+		},
+		{
+			name:       "Approvals only",
+			query:      contract.HistoryQuery{Events: []string{contract.EventApproval}, ToBlock: toBlock(105)},
+			wantBlocks: []uint64{103},
+			wantNames:  []string{"Approval"},
+		},
+		{
+			name:  "Up to latest block",
+			query: contract.HistoryQuery{FromBlock: 104},
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().HeaderByNumber(context.Background(), nil).Return(&types.Header{Number: big.NewInt(105)}, nil)
+			},
+			wantBlocks: []uint64{105, 105},
+		},
+		{
+			name:    "Error invalid block range",
+			query:   contract.HistoryQuery{FromBlock: 106, ToBlock: toBlock(105)},
+			wantErr: contract.ErrInvalidBlockRange,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			// the number of log queries depends on the query, they get their own mock so the expectation
+			// below does not outlive the subtest
+			clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+			c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+			clientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(16), nil)
+			clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(filterLogs(logs)).AnyTimes()
+			if tt.prepare != nil {
+				tt.prepare(clientMock)
+			}
+
+			var blocks []uint64
+			var names []string
+
+			err := c.History(tt.query, func(ev contract.Event) error {
+				blocks = append(blocks, ev.BlockNumber)
+				names = append(names, ev.Name)
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), tt.wantBlocks, blocks)
+			if tt.wantNames != nil {
+				assert.Equal(ts.T(), tt.wantNames, names)
+			}
+		})
+	}
+
+	ts.Run("Decodes event fields", func() {
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(16), nil)
+		ts.ClientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(filterLogs(logs)).Times(2)
+
+		var events []contract.Event
+		err := ts.Contract.History(contract.HistoryQuery{FromBlock: 103, ToBlock: toBlock(103)}, func(ev contract.Event) error {
+			events = append(events, ev)
+			return nil
+		})
+
+		assert.NoError(ts.T(), err)
+		assert.Equal(ts.T(), contract.Event{
+			Name:        contract.EventTransfer,
+			BlockNumber: 103,
+			TxHash:      logs[1].TxHash,
+			LogIndex:    1,
+			From:        testAddr,
+			To:          spenderAddr,
+			Value:       big.NewInt(10),
+		}, events[1])
+	})
+
+	ts.Run("Error FilterLogs", func() {
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(16), nil)
+		ts.ClientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return(nil, errors.New("query returned more than 10000 results"))
+
+		err := ts.Contract.History(contract.HistoryQuery{ToBlock: toBlock(105)}, func(contract.Event) error { return nil })
+		assert.Error(ts.T(), err)
+	})
+
+	ts.Run("Error from callback stops query", func() {
+		stop := errors.New("stop")
+
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(16), nil)
+		ts.ClientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(filterLogs(logs)).Times(2)
+
+		calls := 0
+		err := ts.Contract.History(contract.HistoryQuery{ToBlock: toBlock(105), ChunkSize: 2}, func(contract.Event) error {
+			calls++
+			return stop
+		})
+
+		assert.ErrorIs(ts.T(), err, stop)
+		assert.Equal(ts.T(), 1, calls)
+	})
+}
@@ -0,0 +1,426 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/index"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// ErrNoIndex is returned when events are synced to or read from the local index but none was configured
+var ErrNoIndex = errors.New("no event index configured")
+
+// IndexQuery configures a `SyncIndex` run.
+type IndexQuery struct {
+	// ChunkSize is the number of blocks queried at once, defaults to `DefaultChunkSize`
+	ChunkSize uint64
+	// Confirmations keeps the latest blocks out of the index, reorgs deeper than that are still rolled back
+	Confirmations uint64
+}
+
+// IndexResult summarizes a `SyncIndex` run, nothing was indexed when `FromBlock` is past `ToBlock`.
+type IndexResult struct {
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+	// Events is the number of events indexed, including the ones a reorg during the run rolled back again
+	Events int `json:"events"`
+	// Reorg is set when the indexed chain was reorganized since the last run or during this one
+	Reorg *Reorg `json:"reorg,omitempty"`
+}
+
+// Reorg describes the events rolled back after a reorg, blocks after the common ancestor are indexed again.
+type Reorg struct {
+	Ancestor uint64 `json:"ancestor"`
+	Removed  int    `json:"removed"`
+}
+
+// SyncIndex indexes the Transfer and Approval events of the deployed token from the last checkpoint up to the
+// latest block. Before indexing new blocks the checkpoint hash is compared against the chain, when it differs
+// the index is rolled back to the latest block whose hash still matches. The same is done when the chain
+// reorganizes while a chunk is fetched, the chunk is fetched again from the new checkpoint.
+func (c *Contract) SyncIndex(query IndexQuery) (*IndexResult, error) {
+	return c.SyncIndexContext(context.Background(), query)
+}
+
+// SyncIndexContext is like `SyncIndex` but every node call is made with the given context. Chunks already
+// indexed are kept when the context is cancelled.
+func (c *Contract) SyncIndexContext(ctx context.Context, query IndexQuery) (*IndexResult, error) {
+	scope, rec, err := c.indexScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filterer, err := goldcoin.NewGoldcoinFilterer(rec.Address, c.Client)
+	if err != nil {
+		log.Err(err).Msg("unable to bind goldcoin filterer")
+		return nil, err
+	}
+
+	// the block before the deploy block, nothing is indexed up to it
+	base := index.Checkpoint{}
+	if rec.BlockNumber > 0 {
+		base.Number = rec.BlockNumber - 1
+	}
+
+	cp, err := c.Index.Checkpoint(scope)
+	if errors.Is(err, index.ErrNotSynced) {
+		cp = &base
+	} else if err != nil {
+		log.Err(err).Msg("unable to read index checkpoint")
+		return nil, err
+	}
+
+	result := &IndexResult{}
+
+	if cp, err = c.rollbackReorg(ctx, scope, cp, base, result); err != nil {
+		return nil, err
+	}
+
+	head, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get latest header")
+		return nil, err
+	}
+
+	end := head.Number.Uint64()
+	if end < query.Confirmations {
+		end = 0
+	} else {
+		end -= query.Confirmations
+	}
+
+	chunk := query.ChunkSize
+	if chunk == 0 {
+		chunk = DefaultChunkSize
+	}
+
+	result.FromBlock, result.ToBlock = cp.Number+1, end
+
+	for from, retries := cp.Number+1, 0; from <= end; {
+		to := from + chunk - 1
+		if to > end || to < from {
+			to = end
+		}
+
+		hash, events, err := c.fetchChunk(ctx, filterer, cp, from, to)
+		if errors.Is(err, errChunkReorged) {
+			if retries++; retries > maxChunkRetries {
+				log.Err(err).Msgf("unable to index blocks %d-%d", from, to)
+				return nil, err
+			}
+
+			log.Warn().Err(err).Uint64("from", from).Uint64("to", to).Msg("reorg while indexing, fetching the blocks again")
+
+			// the blocks indexed by the earlier chunks may be orphaned as well
+			if cp, err = c.rollbackReorg(ctx, scope, cp, base, result); err != nil {
+				return nil, err
+			}
+
+			if cp.Number+1 < result.FromBlock {
+				result.FromBlock = cp.Number + 1
+			}
+
+			from = cp.Number + 1
+
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		indexed := make([]index.Event, 0, len(events))
+		for _, ev := range events {
+			indexed = append(indexed, index.Event{
+				Name:        ev.Name,
+				BlockNumber: ev.BlockNumber,
+				BlockHash:   ev.blockHash,
+				TxHash:      ev.TxHash,
+				LogIndex:    ev.LogIndex,
+				From:        ev.From,
+				To:          ev.To,
+				Value:       ev.Value,
+			})
+		}
+
+		next := index.Checkpoint{Number: to, Hash: hash}
+		if err := c.Index.Apply(scope, indexed, next); err != nil {
+			log.Err(err).Msgf("unable to index blocks %d-%d", from, to)
+			return nil, err
+		}
+
+		result.Events += len(events)
+		cp, retries = &next, 0
+
+		if to == end {
+			break
+		}
+
+		from = to + 1
+	}
+
+	return result, nil
+}
+
+// maxChunkRetries is the number of times in a row a chunk is fetched again because the chain reorganized while
+// it was fetched, before the sync gives up.
+const maxChunkRetries = 3
+
+// errChunkReorged is returned by `fetchChunk` when the chain reorganized while the chunk was fetched
+var errChunkReorged = errors.New("chain reorganized while indexing")
+
+// fetchChunk returns the events of the blocks from-to and the hash of the last one, the checkpoint they are
+// stored under. The hash is fetched before the events and checked again after them, and every event must be in
+// the canonical block of its number, so events of an orphaned fork are never stored under a hash of the new one.
+// It fails with `errChunkReorged` when any of that changed, or when the current checkpoint is no longer canonical.
+func (c *Contract) fetchChunk(ctx context.Context, filterer *goldcoin.GoldcoinFilterer, cp *index.Checkpoint, from, to uint64) (common.Hash, []Event, error) {
+	last, err := c.blockHash(ctx, to)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	events, err := fetchEvents(ctx, filterer, []eventFilter{{}}, from, to)
+	if err != nil {
+		log.Err(err).Msgf("unable to get events of blocks %d-%d", from, to)
+		return common.Hash{}, nil, err
+	}
+
+	canonical := map[uint64]common.Hash{}
+	for _, ev := range events {
+		hash, ok := canonical[ev.BlockNumber]
+		if !ok {
+			if hash, err = c.blockHash(ctx, ev.BlockNumber); err != nil {
+				return common.Hash{}, nil, err
+			}
+
+			canonical[ev.BlockNumber] = hash
+		}
+
+		if ev.blockHash != hash {
+			return common.Hash{}, nil, fmt.Errorf("%w: event of block %d is orphaned", errChunkReorged, ev.BlockNumber)
+		}
+	}
+
+	// while the last block keeps its hash so does every block before it
+	for _, b := range []index.Checkpoint{*cp, {Number: to, Hash: last}} {
+		if b.Hash == (common.Hash{}) {
+			continue
+		}
+
+		changed, err := c.blockChanged(ctx, b.Number, b.Hash)
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+
+		if changed {
+			return common.Hash{}, nil, fmt.Errorf("%w: block %d changed", errChunkReorged, b.Number)
+		}
+	}
+
+	return last, events, nil
+}
+
+// rollbackReorg rolls the index back to the latest block whose hash still matches the chain when the checkpoint
+// no longer does, and returns the checkpoint indexing resumes from. The rollback is added to the result.
+func (c *Contract) rollbackReorg(ctx context.Context, scope index.Scope, cp *index.Checkpoint, base index.Checkpoint, result *IndexResult) (*index.Checkpoint, error) {
+	if cp.Hash == (common.Hash{}) {
+		return cp, nil
+	}
+
+	reorged, err := c.blockChanged(ctx, cp.Number, cp.Hash)
+	if err != nil || !reorged {
+		return cp, err
+	}
+
+	ancestor, err := c.commonAncestor(ctx, scope, cp.Number, base)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := c.Index.Rollback(scope, ancestor)
+	if err != nil {
+		log.Err(err).Msg("unable to roll back index")
+		return nil, err
+	}
+
+	log.Warn().Uint64("block", cp.Number).Uint64("ancestor", ancestor.Number).Int("removed", removed).Msg("reorg detected, index rolled back")
+
+	if result.Reorg == nil {
+		result.Reorg = &Reorg{Ancestor: ancestor.Number}
+	} else if ancestor.Number < result.Reorg.Ancestor {
+		result.Reorg.Ancestor = ancestor.Number
+	}
+
+	result.Reorg.Removed += removed
+
+	return &ancestor, nil
+}
+
+// IndexedBalance returns the balance of the account derived from the indexed events, the balance of the owner
+// when the account is empty. Only the chain id is read from the node.
+func (c *Contract) IndexedBalance(account string) (*big.Int, error) {
+	return c.IndexedBalanceContext(context.Background(), account)
+}
+
+// IndexedBalanceContext is like `IndexedBalance` but the chain id is read with the given context.
+func (c *Contract) IndexedBalanceContext(ctx context.Context, account string) (*big.Int, error) {
+	addr, err := c.accountOrOwner(account)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := c.syncedScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Index.Balance(scope, addr)
+}
+
+// IndexedAllowance returns the allowance of the spender derived from the indexed approvals of the owner, the
+// owner defaults to the signer address when empty. Only the chain id is read from the node.
+func (c *Contract) IndexedAllowance(owner string, spender string) (*big.Int, error) {
+	return c.IndexedAllowanceContext(context.Background(), owner, spender)
+}
+
+// IndexedAllowanceContext is like `IndexedAllowance` but the chain id is read with the given context.
+func (c *Contract) IndexedAllowanceContext(ctx context.Context, owner string, spender string) (*big.Int, error) {
+	ownerAddr, err := c.accountOrOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	spenderAddr, err := ParseAddress(spender)
+	if err != nil {
+		log.Err(err).Msg("invalid spender address")
+		return nil, err
+	}
+
+	scope, err := c.syncedScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Index.Allowance(scope, ownerAddr, spenderAddr)
+}
+
+// IndexedHolders returns every address holding tokens according to the index, ordered by address.
+func (c *Contract) IndexedHolders() ([]index.Holding, error) {
+	return c.IndexedHoldersContext(context.Background())
+}
+
+// IndexedHoldersContext is like `IndexedHolders` but the chain id is read with the given context.
+func (c *Contract) IndexedHoldersContext(ctx context.Context) ([]index.Holding, error) {
+	scope, err := c.syncedScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Index.Balances(scope)
+}
+
+// indexScope resolves the index scope of the latest token deployment.
+func (c *Contract) indexScope(ctx context.Context) (index.Scope, *registry.Record, error) {
+	if c.Index == nil {
+		return index.Scope{}, nil, ErrNoIndex
+	}
+
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return index.Scope{}, nil, err
+	}
+
+	return index.Scope{ChainID: rec.ChainID, Token: rec.Address}, rec, nil
+}
+
+// syncedScope is like `indexScope` but fails with `index.ErrNotSynced` when the token was never synced, so
+// balances are not silently served as zero.
+func (c *Contract) syncedScope(ctx context.Context) (index.Scope, error) {
+	scope, _, err := c.indexScope(ctx)
+	if err != nil {
+		return index.Scope{}, err
+	}
+
+	if _, err := c.Index.Checkpoint(scope); err != nil {
+		log.Err(err).Msg("unable to read index checkpoint")
+		return index.Scope{}, err
+	}
+
+	return scope, nil
+}
+
+// blockChanged reports whether the block at the given number no longer has the given hash.
+func (c *Contract) blockChanged(ctx context.Context, number uint64, hash common.Hash) (bool, error) {
+	current, err := c.blockHash(ctx, number)
+	if err != nil {
+		return false, err
+	}
+
+	return current != hash, nil
+}
+
+// rpcClient is implemented by clients exposing their raw rpc connection, such as `*ethclient.Client`.
+type rpcClient interface {
+	Client() *rpc.Client
+}
+
+// blockHash returns the hash the node reports for the canonical block at the given number, the hash its logs
+// carry. Ethermint nodes report the hash of the Tendermint block, which is not the hash of the header they
+// return, so the header is only hashed locally by clients without a raw rpc connection like simulated backends.
+func (c *Contract) blockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	rc, ok := c.Client.(rpcClient)
+	if !ok {
+		header, err := c.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Err(err).Msgf("unable to get header of block %d", number)
+			return common.Hash{}, err
+		}
+
+		return header.Hash(), nil
+	}
+
+	var block struct {
+		Hash *common.Hash `json:"hash"`
+	}
+
+	if err := rc.Client().CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false); err != nil {
+		log.Err(err).Msgf("unable to get block %d", number)
+		return common.Hash{}, err
+	} else if block.Hash == nil {
+		log.Error().Msgf("block %d not found", number)
+		return common.Hash{}, ethereum.NotFound
+	}
+
+	return *block.Hash, nil
+}
+
+// commonAncestor returns the latest indexed block before the given one whose hash still matches the chain, the
+// base checkpoint when none does and the token has to be indexed again from its deploy block.
+func (c *Contract) commonAncestor(ctx context.Context, scope index.Scope, before uint64, base index.Checkpoint) (index.Checkpoint, error) {
+	blocks, err := c.Index.Blocks(scope, before)
+	if err != nil {
+		log.Err(err).Msg("unable to read indexed blocks")
+		return index.Checkpoint{}, err
+	}
+
+	for _, b := range blocks {
+		changed, err := c.blockChanged(ctx, b.Number, b.Hash)
+		if err != nil {
+			return index.Checkpoint{}, err
+		}
+
+		if !changed {
+			return index.Checkpoint{Number: b.Number, Hash: b.Hash}, nil
+		}
+	}
+
+	return base, nil
+}
@@ -0,0 +1,311 @@
+package contract_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/index"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// forkedChain serves headers whose hash changes from the fork block on every time the test reorganizes the chain,
+// fetched is called with every log query once its logs are fetched, before they are returned. A tendermint chain
+// reports block hashes which are not the hash of the header it serves, like Ethermint nodes do.
+type forkedChain struct {
+	mu         sync.Mutex
+	head       uint64
+	forks      []uint64
+	logs       []types.Log
+	fetched    func(q ethereum.FilterQuery)
+	tendermint bool
+}
+
+// hash returns the hash the node reports for the block, the one its logs carry
+func (f *forkedChain) hash(number uint64) common.Hash {
+	if f.tendermint {
+		return crypto.Keccak256Hash([]byte("tendermint"), f.headerOf(number).Hash().Bytes())
+	}
+
+	return f.headerOf(number).Hash()
+}
+
+func (f *forkedChain) headerOf(number uint64) *types.Header {
+	// a block is replaced by every reorg forking at or before it
+	var reorgs byte
+	for _, fork := range f.forks {
+		if number >= fork {
+			reorgs++
+		}
+	}
+
+	return &types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte{reorgs}}
+}
+
+// reorg replaces the blocks from the fork block on and their logs
+func (f *forkedChain) reorg(fork, head uint64, logs ...types.Log) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.forks, f.head = append(f.forks, fork), head
+
+	kept := f.logs[:0]
+	for _, l := range f.logs {
+		if l.BlockNumber < fork {
+			kept = append(kept, l)
+		}
+	}
+
+	f.logs = kept
+	f.add(logs...)
+}
+
+// extend mines the blocks up to head with the logs
+func (f *forkedChain) extend(head uint64, logs ...types.Log) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.head = head
+	f.add(logs...)
+}
+
+func (f *forkedChain) add(logs ...types.Log) {
+	for _, l := range logs {
+		l.BlockHash = f.hash(l.BlockNumber)
+		f.logs = append(f.logs, l)
+	}
+}
+
+func (f *forkedChain) header(_ context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if number == nil {
+		return f.headerOf(f.head), nil
+	}
+
+	return f.headerOf(number.Uint64()), nil
+}
+
+// GetBlockByNumber serves `eth_getBlockByNumber` with the reported hash of the block
+func (f *forkedChain) GetBlockByNumber(number hexutil.Uint64, _ bool) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return map[string]interface{}{"number": number, "hash": f.hash(uint64(number))}, nil
+}
+
+// rpcBackend is a mocked client exposing the raw rpc connection to a chain, like `*ethclient.Client` does
+type rpcBackend struct {
+	*contract.MockIBlockchain
+	rpc *rpc.Client
+}
+
+func (b rpcBackend) Client() *rpc.Client {
+	return b.rpc
+}
+
+func (f *forkedChain) filterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	logs := append([]types.Log(nil), f.logs...)
+	fetched := f.fetched
+	f.mu.Unlock()
+
+	if fetched != nil {
+		fetched(q)
+	}
+
+	return filterLogs(logs)(ctx, q)
+}
+
+// A test function that tests the `SyncIndex` function and the balances and allowances served from the index.
+func (ts *TableSuite) TestSyncIndex() {
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 20, Name: contract.GoldcoinName, Address: spenderAddr, BlockNumber: 100}))
+
+	ix, err := index.Open(ts.T().TempDir())
+	ts.Require().NoError(err)
+	defer ix.Close()
+
+	clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+	c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry), contract.WithIndex(ix), contract.WithSigner(testSigner))
+
+	chain := &forkedChain{head: 105}
+	chain.add(
+		tokenLog(transferTopic, 100, 0, common.Address{}, testAddr, 100),
+		tokenLog(transferTopic, 102, 0, testAddr, holderAddr, 30),
+		tokenLog(approvalTopic, 103, 0, testAddr, spenderAddr, 50),
+	)
+
+	clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(20), nil).AnyTimes()
+	clientMock.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).DoAndReturn(chain.header).AnyTimes()
+	clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(chain.filterLogs).AnyTimes()
+
+	check := func(wantBalance, wantHolder, wantAllowance int64) {
+		bal, err := c.IndexedBalance("")
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(wantBalance), bal)
+
+		bal, err = c.IndexedBalance(holderAddr.Hex())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(wantHolder), bal)
+
+		allowance, err := c.IndexedAllowance("", spenderAddr.Hex())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(wantAllowance), allowance)
+	}
+
+	ts.Run("Reads fail before the first sync", func() {
+		_, err := c.IndexedBalance("")
+		assert.ErrorIs(ts.T(), err, index.ErrNotSynced)
+	})
+
+	ts.Run("Indexes events from deploy block in chunks", func() {
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 100, ToBlock: 105, Events: 3}, result)
+
+		check(70, 30, 50)
+
+		holders, err := c.IndexedHolders()
+		ts.Require().NoError(err)
+		assert.Len(ts.T(), holders, 2)
+	})
+
+	ts.Run("Nothing new to index", func() {
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 106, ToBlock: 105}, result)
+	})
+
+	ts.Run("Rolls back orphaned events after reorg", func() {
+		chain.reorg(103, 106,
+			tokenLog(transferTopic, 104, 0, holderAddr, testAddr, 10),
+			tokenLog(approvalTopic, 104, 1, testAddr, spenderAddr, 5),
+		)
+
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 103, ToBlock: 106, Events: 2, Reorg: &contract.Reorg{Ancestor: 102, Removed: 1}}, result)
+
+		check(80, 20, 5)
+	})
+
+	ts.Run("Reindexes from deploy block when no block matches", func() {
+		chain.reorg(0, 106,
+			tokenLog(transferTopic, 101, 0, common.Address{}, testAddr, 7),
+		)
+
+		result, err := c.SyncIndex(contract.IndexQuery{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 100, ToBlock: 106, Events: 1, Reorg: &contract.Reorg{Ancestor: 99, Removed: 4}}, result)
+
+		check(7, 0, 0)
+	})
+
+	ts.Run("Fetches a chunk again when the chain reorganizes while it is fetched", func() {
+		chain.extend(110, tokenLog(transferTopic, 108, 0, testAddr, holderAddr, 5))
+		// the logs fetched are of the orphaned fork, the header of the chunk's last block of the new one
+		var once sync.Once
+		chain.fetched = func(ethereum.FilterQuery) {
+			once.Do(func() { chain.reorg(108, 110, tokenLog(transferTopic, 109, 0, testAddr, holderAddr, 3)) })
+		}
+		defer func() { chain.fetched = nil }()
+
+		result, err := c.SyncIndex(contract.IndexQuery{})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 107, ToBlock: 110, Events: 1}, result)
+
+		check(4, 3, 0)
+
+		result, err = c.SyncIndex(contract.IndexQuery{})
+		ts.Require().NoError(err)
+		assert.Nil(ts.T(), result.Reorg)
+	})
+
+	ts.Run("Rolls back the chunks indexed before a reorg while indexing", func() {
+		chain.extend(114, tokenLog(transferTopic, 112, 0, testAddr, holderAddr, 2))
+		// the first chunk is orphaned while the second chunk is fetched
+		var once sync.Once
+		chain.fetched = func(q ethereum.FilterQuery) {
+			if q.FromBlock.Uint64() == 113 {
+				once.Do(func() { chain.reorg(111, 114) })
+			}
+		}
+		defer func() { chain.fetched = nil }()
+
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 111, ToBlock: 114, Events: 1, Reorg: &contract.Reorg{Ancestor: 110, Removed: 1}}, result)
+
+		check(4, 3, 0)
+	})
+
+	ts.Run("No index configured", func() {
+		_, err := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry)).SyncIndex(contract.IndexQuery{})
+		assert.ErrorIs(ts.T(), err, contract.ErrNoIndex)
+	})
+}
+
+// A test function that tests `SyncIndex` against a node reporting block hashes which are not the hash of its headers.
+func (ts *TableSuite) TestSyncIndexReportedHashes() {
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 22, Name: contract.GoldcoinName, Address: spenderAddr, BlockNumber: 100}))
+
+	ix, err := index.Open(ts.T().TempDir())
+	ts.Require().NoError(err)
+	defer ix.Close()
+
+	chain := &forkedChain{head: 105, tendermint: true}
+	chain.add(
+		tokenLog(transferTopic, 100, 0, common.Address{}, testAddr, 100),
+		tokenLog(transferTopic, 102, 0, testAddr, holderAddr, 30),
+		tokenLog(transferTopic, 103, 0, testAddr, holderAddr, 20),
+	)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	ts.Require().NoError(server.RegisterName("eth", chain))
+
+	clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+	clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(22), nil).AnyTimes()
+	clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(chain.header).AnyTimes()
+	clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(chain.filterLogs).AnyTimes()
+
+	backend := rpcBackend{MockIBlockchain: clientMock, rpc: rpc.DialInProc(server)}
+	defer backend.rpc.Close()
+
+	ts.Require().NotEqual(chain.headerOf(102).Hash(), chain.hash(102))
+
+	c := contract.NewContract(backend, contract.WithRegistry(ts.Registry), contract.WithIndex(ix), contract.WithSigner(testSigner))
+
+	ts.Run("Indexes the events carrying the reported hashes", func() {
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 100, ToBlock: 105, Events: 3}, result)
+
+		bal, err := c.IndexedBalance(holderAddr.Hex())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(50), bal)
+	})
+
+	ts.Run("Rolls back to the latest block whose reported hash matches", func() {
+		chain.reorg(103, 106, tokenLog(transferTopic, 104, 0, testAddr, holderAddr, 5))
+
+		result, err := c.SyncIndex(contract.IndexQuery{ChunkSize: 2})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.IndexResult{FromBlock: 103, ToBlock: 106, Events: 1, Reorg: &contract.Reorg{Ancestor: 102, Removed: 1}}, result)
+
+		bal, err := c.IndexedBalance(holderAddr.Hex())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(35), bal)
+	})
+}
@@ -0,0 +1,99 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// TokenInfo holds the metadata of the deployed token along with where and when it was deployed.
+type TokenInfo struct {
+	Name        string         `json:"name"`
+	Symbol      string         `json:"symbol"`
+	Decimals    uint8          `json:"decimals"`
+	TotalSupply *big.Int       `json:"totalSupply"`
+	ChainID     uint64         `json:"chainId"`
+	Address     common.Address `json:"address"`
+	// CodeHash is the keccak256 hash of the runtime code at the address
+	CodeHash common.Hash `json:"codeHash"`
+	DeployTx common.Hash `json:"deployTx"`
+	// DeployBlock is zero while the deploy transaction is pending
+	DeployBlock uint64 `json:"deployBlock"`
+}
+
+// Units returns the decimals and symbol of the token to convert amounts with.
+func (i *TokenInfo) Units() TokenUnits {
+	return TokenUnits{Decimals: i.Decimals, Symbol: i.Symbol}
+}
+
+// TokenInfo reads the metadata of the latest deployed token, instance must be bound to that deployment
+func (c *Contract) TokenInfo(instance IGoldcoin) (*TokenInfo, error) {
+	return c.TokenInfoContext(context.Background(), instance)
+}
+
+// TokenInfoContext is like `TokenInfo` but every call is made with the given context.
+func (c *Contract) TokenInfoContext(ctx context.Context, instance IGoldcoin) (*TokenInfo, error) {
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{
+		ChainID:     rec.ChainID,
+		Address:     rec.Address,
+		DeployTx:    rec.TxHash,
+		DeployBlock: rec.BlockNumber,
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	if info.Name, err = instance.Name(opts); err != nil {
+		log.Err(err).Msg("unable to get token name")
+		return nil, err
+	}
+
+	units, err := c.TokenUnitsContext(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Symbol, info.Decimals = units.Symbol, units.Decimals
+
+	if info.TotalSupply, err = instance.TotalSupply(opts); err != nil {
+		log.Err(err).Msg("unable to get token total supply")
+		return nil, err
+	}
+
+	code, err := c.Client.CodeAt(ctx, rec.Address, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get contract code")
+		return nil, err
+	}
+
+	info.CodeHash = crypto.Keccak256Hash(code)
+
+	// deployments recorded without waiting are resolved from their reciept once mined
+	if info.DeployBlock == 0 {
+		reciept, err := c.Client.TransactionReceipt(ctx, rec.TxHash)
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+		case err != nil:
+			log.Err(err).Msg("unable to get deploy transaction reciept")
+			return nil, err
+		default:
+			if err := c.markMined(rec, reciept); err != nil {
+				log.Err(err).Msg("unable to update deployment block number in registry")
+			}
+
+			info.DeployBlock = rec.BlockNumber
+		}
+	}
+
+	return info, nil
+}
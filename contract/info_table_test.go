@@ -0,0 +1,121 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// A test function that tests the `TokenInfo` function.
+func (ts *TableSuite) TestTokenInfo() {
+	contractAddr := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	txHash := common.HexToHash("0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b")
+	code := []byte{0x60, 0x80, 0x60, 0x40}
+
+	// mined deployment on chain 8, pending ones on chains 10 and 12
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 8, Name: contract.GoldcoinName, Address: contractAddr, TxHash: txHash, BlockNumber: 42}))
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 10, Name: contract.GoldcoinName, Address: contractAddr, TxHash: txHash}))
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 12, Name: contract.GoldcoinName, Address: contractAddr, TxHash: txHash}))
+
+	expectMetadata := func(mg *contract.MockIGoldcoin) {
+		mg.EXPECT().Name(gomock.Any()).Return("Goldcoin", nil)
+		mg.EXPECT().Decimals(gomock.Any()).Return(uint8(18), nil)
+		mg.EXPECT().Symbol(gomock.Any()).Return("GLD", nil)
+		mg.EXPECT().TotalSupply(gomock.Any()).Return(testBalance, nil)
+	}
+
+	subtests := []struct {
+		name      string
+		prepare   func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin)
+		wantBlock uint64
+		wantErr   bool
+	}{
+		{
+			name: "Reads metadata of mined deployment",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(8), nil)
+				expectMetadata(mg)
+				m.EXPECT().CodeAt(context.Background(), contractAddr, nil).Return(code, nil)
+			},
+			wantBlock: 42,
+		},
+		{
+			name: "Resolves deploy block from reciept",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(10), nil)
+				expectMetadata(mg)
+				m.EXPECT().CodeAt(context.Background(), contractAddr, nil).Return(code, nil)
+				m.EXPECT().TransactionReceipt(context.Background(), txHash).Return(&types.Receipt{TxHash: txHash, BlockNumber: big.NewInt(7)}, nil)
+			},
+			wantBlock: 7,
+		},
+		{
+			name: "Error TotalSupply",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(8), nil)
+				mg.EXPECT().Name(gomock.Any()).Return("Goldcoin", nil)
+				mg.EXPECT().Decimals(gomock.Any()).Return(uint8(18), nil)
+				mg.EXPECT().Symbol(gomock.Any()).Return("GLD", nil)
+				mg.EXPECT().TotalSupply(gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error CodeAt",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(8), nil)
+				expectMetadata(mg)
+				m.EXPECT().CodeAt(context.Background(), contractAddr, nil).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error no deployment",
+			prepare: func(m *contract.MockIBlockchain, mg *contract.MockIGoldcoin) {
+				m.EXPECT().ChainID(context.Background()).Return(big.NewInt(14), nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			tt.prepare(ts.ClientMock, ts.GoldcoinMock)
+
+			info, err := ts.Contract.TokenInfo(ts.GoldcoinMock)
+			if tt.wantErr {
+				assert.Error(ts.T(), err)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), "Goldcoin", info.Name)
+			assert.Equal(ts.T(), contract.TokenUnits{Decimals: 18, Symbol: "GLD"}, info.Units())
+			assert.Equal(ts.T(), testBalance, info.TotalSupply)
+			assert.Equal(ts.T(), contractAddr, info.Address)
+			assert.Equal(ts.T(), crypto.Keccak256Hash(code), info.CodeHash)
+			assert.Equal(ts.T(), tt.wantBlock, info.DeployBlock)
+		})
+	}
+
+	ts.Run("Pending deployment keeps zero deploy block", func() {
+		ts.ClientMock.EXPECT().ChainID(context.Background()).Return(big.NewInt(12), nil)
+		ts.ClientMock.EXPECT().CodeAt(context.Background(), contractAddr, nil).Return(nil, nil)
+		ts.ClientMock.EXPECT().TransactionReceipt(context.Background(), txHash).Return(nil, ethereum.NotFound)
+		expectMetadata(ts.GoldcoinMock)
+
+		info, err := contract.NewContract(ts.ClientMock, contract.WithRegistry(ts.Registry)).TokenInfo(ts.GoldcoinMock)
+		assert.NoError(ts.T(), err)
+		assert.Equal(ts.T(), uint64(0), info.DeployBlock)
+	})
+}
@@ -0,0 +1,206 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+// NonceManager allocates the nonces of the transactions sent from each account locally, so transactions sent in
+// quick succession or from several goroutines get consecutive nonces even when the pending nonce reported by the
+// node lags behind the transactions it just accepted. It is safe for concurrent use.
+//
+// The nonce of an account is reserved from the moment it is allocated until the transaction carrying it is sent,
+// so transactions of an account reach the node in nonce order. The allocation follows the node again when it
+// rejects a nonce as too low or too high, when the account sent transactions through another tool, and when
+// transactions sent earlier were dropped from the pool, leaving a gap the next transactions would be stuck behind.
+type NonceManager struct {
+	client IBlockchain
+
+	mu       sync.Mutex
+	accounts map[common.Address]*nonceAccount
+}
+
+type nonceAccount struct {
+	// lock is held from the allocation of a nonce until the transaction carrying it is sent or given up
+	lock chan struct{}
+	// reserved is the nonce handed out while lock is held and gen counts the reservations, they are guarded by the
+	// mutex of the manager as they are read by goroutines sending transactions with a nonce of their own
+	reserved uint64
+	gen      uint64
+	held     bool
+	// next is the nonce allocated next, unknown until synced with the node
+	next   uint64
+	synced bool
+	// sent are the transactions sent by nonce, from the pending nonce of the node on
+	sent map[uint64]common.Hash
+}
+
+// NewNonceManager returns a nonce manager syncing with the given node.
+func NewNonceManager(client IBlockchain) *NonceManager {
+	return &NonceManager{client: client, accounts: map[common.Address]*nonceAccount{}}
+}
+
+// WithNonceManager shares a nonce manager between several `Contract`s sending from the same accounts, every
+// `Contract` has its own otherwise.
+func WithNonceManager(m *NonceManager) Option {
+	return func(c *Contract) {
+		c.Nonces = m
+	}
+}
+
+// Next reserves the next nonce of the account, waiting for the transaction holding the previous reservation to be
+// sent. The reservation is ended by `Sent` once the transaction carrying the nonce is sent, release ends it when no
+// transaction is sent and does nothing after `Sent`, it is meant to be deferred.
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, func(), error) {
+	a := m.account(account)
+
+	select {
+	case a.lock <- struct{}{}:
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+
+	reserved := false
+	defer func() {
+		if !reserved {
+			<-a.lock
+		}
+	}()
+
+	nonce, err := m.sync(ctx, account, a)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	m.mu.Lock()
+	a.gen++
+	a.reserved, a.held = nonce, true
+	gen := a.gen
+	m.mu.Unlock()
+
+	reserved = true
+
+	release := func() {
+		if m.end(a, func() bool { return a.gen == gen }) {
+			<-a.lock
+		}
+	}
+
+	return nonce, release, nil
+}
+
+// sync returns the next nonce of the account, following the node when the local allocation is unknown, behind,
+// or ahead of transactions that were dropped.
+func (m *NonceManager) sync(ctx context.Context, account common.Address, a *nonceAccount) (uint64, error) {
+	pending, err := m.client.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Err(err).Msg("unable to get nonce")
+		return 0, err
+	}
+
+	for nonce := range a.sent {
+		if nonce < pending {
+			delete(a.sent, nonce)
+		}
+	}
+
+	switch {
+	case !a.synced || pending > a.next:
+		// first transaction of the account, after a rejected nonce, or the account sent transactions elsewhere
+		a.next, a.synced = pending, true
+	case pending < a.next:
+		// the node may not count the transactions in its pool yet, only the transaction at its pending nonce tells
+		// whether the ones sent since were dropped
+		hash, ok := a.sent[pending]
+		if !ok {
+			break
+		}
+
+		if _, _, err := m.client.TransactionByHash(ctx, hash); errors.Is(err, ethereum.NotFound) {
+			log.Warn().Msgf("transaction %s with nonce %d was dropped, sending from nonce %d again", hash.Hex(), pending, pending)
+
+			for nonce := range a.sent {
+				delete(a.sent, nonce)
+			}
+
+			a.next = pending
+		} else if err != nil {
+			log.Err(err).Msg("unable to get transaction")
+			return 0, err
+		}
+	}
+
+	return a.next, nil
+}
+
+// Sent ends the reservation of the account with the outcome of sending the transaction carrying the nonce. A
+// nonce rejected by the node as too low or too high makes the next allocation follow the node.
+func (m *NonceManager) Sent(account common.Address, tx *types.Transaction, err error) {
+	a := m.account(account)
+	if !m.end(a, func() bool { return a.reserved == tx.Nonce() }) {
+		return
+	}
+
+	switch {
+	case err == nil:
+		a.sent[tx.Nonce()] = tx.Hash()
+		a.next = tx.Nonce() + 1
+	case IsNonceError(err):
+		log.Warn().Err(err).Msgf("nonce %d rejected, resyncing with the node", tx.Nonce())
+		a.synced = false
+	}
+
+	<-a.lock
+}
+
+// end ends the current reservation of the account when it matches, it reports false when it does not: the
+// reservation already ended, or a transaction is sent with a nonce set by the caller, which is not tracked.
+func (m *NonceManager) end(a *nonceAccount, matches func() bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !a.held || !matches() {
+		return false
+	}
+
+	a.held = false
+
+	return true
+}
+
+func (m *NonceManager) account(account common.Address) *nonceAccount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.accounts[account]
+	if !ok {
+		a = &nonceAccount{lock: make(chan struct{}, 1), sent: map[uint64]common.Hash{}}
+		m.accounts[account] = a
+	}
+
+	return a
+}
+
+// IsNonceError reports whether the node rejected a transaction for its nonce, geth reports nonces that are too low
+// or too high, evmos an invalid nonce or sequence.
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"nonce too low", "nonce too high", "invalid nonce", "invalid sequence"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
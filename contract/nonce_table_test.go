@@ -0,0 +1,171 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// A test function that tests allocating nonces locally and following the node when it disagrees.
+func (ts *TableSuite) TestNonceManager() {
+	newMock := func(pending *uint64) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).DoAndReturn(func(context.Context, interface{}) (uint64, error) {
+			return *pending, nil
+		}).AnyTimes()
+
+		return m
+	}
+
+	send := func(m *contract.NonceManager, err error) uint64 {
+		nonce, release, nextErr := m.Next(context.Background(), testAddr)
+		ts.Require().NoError(nextErr)
+		defer release()
+
+		m.Sent(testAddr, types.NewTx(&types.LegacyTx{Nonce: nonce}), err)
+
+		return nonce
+	}
+
+	ts.Run("Allocates consecutive nonces while the node lags", func() {
+		pending := uint64(3)
+		mock := newMock(&pending)
+		mock.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).Return(types.NewTx(&types.LegacyTx{}), true, nil)
+		m := contract.NewNonceManager(mock)
+
+		assert.Equal(ts.T(), uint64(3), send(m, nil))
+		assert.Equal(ts.T(), uint64(4), send(m, nil))
+
+		// the node caught up with the transactions sent
+		pending = 5
+		assert.Equal(ts.T(), uint64(5), send(m, nil))
+	})
+
+	ts.Run("Allocates unique nonces to concurrent senders", func() {
+		pending := uint64(0)
+		mock := newMock(&pending)
+		mock.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).Return(types.NewTx(&types.LegacyTx{}), true, nil).AnyTimes()
+		m := contract.NewNonceManager(mock)
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			nonces []int
+		)
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				nonce := send(m, nil)
+
+				mu.Lock()
+				nonces = append(nonces, int(nonce))
+				mu.Unlock()
+			}()
+		}
+
+		wg.Wait()
+		sort.Ints(nonces)
+
+		for i, nonce := range nonces {
+			assert.Equal(ts.T(), i, nonce)
+		}
+	})
+
+	ts.Run("Reuses the nonce of a transaction that was not sent", func() {
+		pending := uint64(7)
+		m := contract.NewNonceManager(newMock(&pending))
+
+		nonce, release, err := m.Next(context.Background(), testAddr)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), uint64(7), nonce)
+		release()
+
+		assert.Equal(ts.T(), uint64(7), send(m, errors.New("insufficient funds for gas * price + value")))
+		assert.Equal(ts.T(), uint64(7), send(m, nil))
+	})
+
+	ts.Run("Resyncs after a rejected nonce", func() {
+		pending := uint64(2)
+		m := contract.NewNonceManager(newMock(&pending))
+
+		assert.Equal(ts.T(), uint64(2), send(m, nil))
+
+		// another tool sent from the account meanwhile
+		pending = 9
+		assert.Equal(ts.T(), uint64(9), send(m, errors.New("invalid nonce; got 9, expected 6: invalid sequence")))
+
+		// the node rejected the nonce, the allocation follows it even below the nonces sent
+		pending = 6
+		assert.Equal(ts.T(), uint64(6), send(m, nil))
+	})
+
+	ts.Run("Fills the gap left by a dropped transaction", func() {
+		pending := uint64(4)
+		mock := newMock(&pending)
+		m := contract.NewNonceManager(mock)
+
+		dropped := types.NewTx(&types.LegacyTx{Nonce: 4})
+
+		nonce, release, err := m.Next(context.Background(), testAddr)
+		ts.Require().NoError(err)
+		m.Sent(testAddr, dropped, nil)
+		release()
+		assert.Equal(ts.T(), uint64(4), nonce)
+
+		mock.EXPECT().TransactionByHash(gomock.Any(), dropped.Hash()).Return(nil, false, ethereum.NotFound)
+		assert.Equal(ts.T(), uint64(4), send(m, nil))
+	})
+
+	ts.Run("Gives up waiting for the previous transaction", func() {
+		pending := uint64(0)
+		m := contract.NewNonceManager(newMock(&pending))
+
+		_, release, err := m.Next(context.Background(), testAddr)
+		ts.Require().NoError(err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err = m.Next(ctx, testAddr)
+		assert.ErrorIs(ts.T(), err, context.Canceled)
+	})
+
+	ts.Run("Sends deployments in quick succession with consecutive nonces", func() {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(36), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(100)}, nil).AnyTimes()
+		m.EXPECT().SuggestGasTipCap(gomock.Any()).Return(big.NewInt(7), nil).AnyTimes()
+		m.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(uint64(900000), nil).AnyTimes()
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).Return(uint64(3), nil).AnyTimes()
+		m.EXPECT().TransactionByHash(gomock.Any(), gomock.Any()).Return(types.NewTx(&types.LegacyTx{}), true, nil).AnyTimes()
+
+		var sent []uint64
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			sent = append(sent, tx.Nonce())
+			return nil
+		}).Times(2)
+
+		c := contract.NewContract(m, contract.WithSigner(testSigner), contract.WithRegistry(ts.Registry))
+
+		for i := 0; i < 2; i++ {
+			_, _, err := c.DeployArtifact(contract.GoldcoinArtifact())
+			ts.Require().NoError(err)
+		}
+
+		assert.Equal(ts.T(), []uint64{3, 4}, sent)
+	})
+}
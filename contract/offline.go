@@ -0,0 +1,356 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// UnsignedTx is a transaction built on a machine connected to the node, to be signed with `Sign` on one holding the
+// key and sent with `Broadcast`. Its fields are the ones an operator reviews before signing, amounts are in base
+// units, the fee fields are either the gas price of a legacy transaction or the fee caps of a dynamic fee one.
+type UnsignedTx struct {
+	ChainID uint64         `json:"chainId"`
+	From    common.Address `json:"from"`
+	// To is nil for contract creations
+	To        *common.Address `json:"to"`
+	Nonce     uint64          `json:"nonce"`
+	Gas       uint64          `json:"gas"`
+	GasPrice  *big.Int        `json:"gasPrice,omitempty"`
+	GasFeeCap *big.Int        `json:"maxFeePerGas,omitempty"`
+	GasTipCap *big.Int        `json:"maxPriorityFeePerGas,omitempty"`
+	Value     *big.Int        `json:"value"`
+	Data      hexutil.Bytes   `json:"data"`
+	// Deployment describes the contract a creation deploys, it is recorded in the registry when broadcast
+	Deployment *Deployment `json:"deployment,omitempty"`
+}
+
+// Deployment is the registry record of a contract deployed by an offline signed transaction.
+type Deployment struct {
+	Name    string          `json:"name"`
+	Address common.Address  `json:"address"`
+	ABI     json.RawMessage `json:"abi"`
+	Bin     string          `json:"bin"`
+}
+
+// SignedTx is an `UnsignedTx` signed by `Sign`, Raw is the RLP encoded transaction submitted to the node.
+type SignedTx struct {
+	ChainID    uint64         `json:"chainId"`
+	From       common.Address `json:"from"`
+	Hash       common.Hash    `json:"hash"`
+	Raw        hexutil.Bytes  `json:"raw"`
+	Deployment *Deployment    `json:"deployment,omitempty"`
+}
+
+// ParseUnsignedTx reads a transaction written by the build functions, unknown fields are rejected.
+func ParseUnsignedTx(data []byte) (*UnsignedTx, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	u := &UnsignedTx{}
+	if err := dec.Decode(u); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	if _, err := u.Transaction(); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// Transaction returns the unsigned transaction described.
+func (u *UnsignedTx) Transaction() (*types.Transaction, error) {
+	if u.ChainID == 0 {
+		return nil, fmt.Errorf("%w: no chain id", ErrInvalidTransaction)
+	}
+
+	value := u.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	switch {
+	case u.GasPrice != nil && u.GasFeeCap == nil && u.GasTipCap == nil:
+		return types.NewTx(&types.LegacyTx{
+			Nonce: u.Nonce, GasPrice: u.GasPrice, Gas: u.Gas, To: u.To, Value: value, Data: u.Data,
+		}), nil
+	case u.GasPrice == nil && u.GasFeeCap != nil && u.GasTipCap != nil:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID: new(big.Int).SetUint64(u.ChainID), Nonce: u.Nonce, GasTipCap: u.GasTipCap, GasFeeCap: u.GasFeeCap,
+			Gas: u.Gas, To: u.To, Value: value, Data: u.Data,
+		}), nil
+	default:
+		return nil, fmt.Errorf("%w: either gasPrice or maxFeePerGas and maxPriorityFeePerGas must be set", ErrInvalidTransaction)
+	}
+}
+
+// ParseSignedTx reads a transaction written by `Sign`, or a bare 0x hex raw transaction signed by another tool.
+func ParseSignedTx(data []byte) (*SignedTx, error) {
+	text := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(text, "0x") {
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+
+		s := &SignedTx{}
+		if err := dec.Decode(s); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		}
+
+		if _, err := s.Transaction(); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	}
+
+	raw, err := hexutil.Decode(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	return &SignedTx{ChainID: tx.ChainId().Uint64(), From: from, Hash: tx.Hash(), Raw: raw}, nil
+}
+
+// Transaction decodes the raw transaction, checking it is signed by From for ChainID and, along with a
+// Deployment, that it creates the contract at the recorded address.
+func (s *SignedTx) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(s.Raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	if tx.ChainId().Uint64() != s.ChainID {
+		return nil, fmt.Errorf("%w: signed for chain %d, expected %d", ErrInvalidTransaction, tx.ChainId(), s.ChainID)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	if from != s.From {
+		return nil, fmt.Errorf("%w: signed by %s, expected %s", ErrInvalidTransaction, from.Hex(), s.From.Hex())
+	}
+
+	if d := s.Deployment; d != nil {
+		if tx.To() != nil {
+			return nil, fmt.Errorf("%w: deployment of %s is a call to %s", ErrInvalidTransaction, d.Name, tx.To().Hex())
+		}
+
+		if address := crypto.CreateAddress(from, tx.Nonce()); d.Address != address {
+			return nil, fmt.Errorf("%w: deployment of %s is recorded at %s, it creates %s", ErrInvalidTransaction, d.Name, d.Address.Hex(), address.Hex())
+		}
+	}
+
+	return tx, nil
+}
+
+// BuildTransfer builds the transaction of `TransferTokens` sent from the given address, to be signed offline.
+// The balance of the sender is checked like when sending.
+func (c *Contract) BuildTransfer(from common.Address, instance IGoldcoin, recieverAddr string, amountStr string) (*UnsignedTx, error) {
+	return c.BuildTransferContext(context.Background(), from, instance, recieverAddr, amountStr)
+}
+
+// BuildTransferContext is like `BuildTransfer` but every node call is bound to the given context.
+func (c *Contract) BuildTransferContext(ctx context.Context, from common.Address, instance IGoldcoin, recieverAddr string, amountStr string) (*UnsignedTx, error) {
+	to, amount, err := parseSpend(recieverAddr, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBalance(ctx, instance, from, amount); err != nil {
+		return nil, err
+	}
+
+	return c.build(ctx, from, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.Transfer(auth, to, amount)
+	})
+}
+
+// BuildApprove builds the transaction of `Approve` sent from the given address, to be signed offline.
+func (c *Contract) BuildApprove(from common.Address, instance IGoldcoin, spender string, amountStr string) (*UnsignedTx, error) {
+	return c.BuildApproveContext(context.Background(), from, instance, spender, amountStr)
+}
+
+// BuildApproveContext is like `BuildApprove` but every node call is bound to the given context.
+func (c *Contract) BuildApproveContext(ctx context.Context, from common.Address, instance IGoldcoin, spender string, amountStr string) (*UnsignedTx, error) {
+	spenderAddr, amount, err := parseSpend(spender, amountStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.build(ctx, from, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.Approve(auth, spenderAddr, amount)
+	})
+}
+
+// BuildDeploy builds the deployment of the artifact from the given address, to be signed offline. The deployment
+// is recorded in the registry once the signed transaction is broadcast.
+func (c *Contract) BuildDeploy(from common.Address, a *Artifact, args ...string) (*UnsignedTx, error) {
+	return c.BuildDeployContext(context.Background(), from, a, args...)
+}
+
+// BuildDeployContext is like `BuildDeploy` but every node call is bound to the given context.
+func (c *Contract) BuildDeployContext(ctx context.Context, from common.Address, a *Artifact, args ...string) (*UnsignedTx, error) {
+	params, err := ParseArguments(a.ABI.Constructor.Inputs, args)
+	if err != nil {
+		log.Err(err).Msgf("invalid constructor arguments of %s", a.Name)
+		return nil, err
+	}
+
+	var address common.Address
+
+	u, err := c.build(ctx, from, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		addr, tx, _, err := DeployContract(auth, a.ABI, a.Bin, c.backend(), params...)
+		address = addr
+
+		return tx, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.Deployment = &Deployment{Name: a.Name, Address: address, ABI: json.RawMessage(a.abiJSON), Bin: hexutil.Encode(a.Bin)}
+
+	return u, nil
+}
+
+// build assembles the transaction made by send like when sending it, but leaves it unsigned and unsent.
+func (c *Contract) build(ctx context.Context, from common.Address, send func(auth *bind.TransactOpts) (*types.Transaction, error)) (*UnsignedTx, error) {
+	var chainID *big.Int
+
+	auth, err := c.txOpts(ctx, from, func(tx *types.Transaction, id *big.Int) (*types.Transaction, error) {
+		chainID = id
+		return tx, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the transaction is sent by another process once signed, its nonce is not allocated locally
+	nonce, err := c.Client.PendingNonceAt(ctx, from)
+	if err != nil {
+		log.Err(err).Msg("unable to get nonce")
+		return nil, err
+	}
+
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.NoSend = true
+
+	tx, err := send(auth)
+	if err != nil {
+		log.Err(err).Msg("unable to build transaction")
+		return nil, err
+	}
+
+	u := &UnsignedTx{
+		ChainID: chainID.Uint64(),
+		From:    from,
+		To:      tx.To(),
+		Nonce:   tx.Nonce(),
+		Gas:     tx.Gas(),
+		Value:   tx.Value(),
+		Data:    tx.Data(),
+	}
+
+	if tx.Type() == types.LegacyTxType {
+		u.GasPrice = tx.GasPrice()
+	} else {
+		u.GasFeeCap, u.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	}
+
+	return u, nil
+}
+
+// Sign signs the transaction with the signer, it makes no node call so it runs on a machine without network
+// access. The signer must be the account the transaction was built for.
+func Sign(u *UnsignedTx, s Signer) (*SignedTx, error) {
+	if s == nil {
+		log.Err(ErrNoSigner).Msg("unable to sign transaction")
+		return nil, ErrNoSigner
+	}
+
+	if s.Address() != u.From {
+		err := fmt.Errorf("%w: transaction is sent from %s, signer is %s", ErrWrongSigner, u.From.Hex(), s.Address().Hex())
+		log.Err(err).Msg("unable to sign transaction")
+		return nil, err
+	}
+
+	tx, err := u.Transaction()
+	if err != nil {
+		log.Err(err).Msg("unable to sign transaction")
+		return nil, err
+	}
+
+	signed, err := s.SignTx(tx, new(big.Int).SetUint64(u.ChainID))
+	if err != nil {
+		log.Err(err).Msg("unable to sign transaction")
+		return nil, err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		log.Err(err).Msg("unable to encode signed transaction")
+		return nil, err
+	}
+
+	return &SignedTx{ChainID: u.ChainID, From: u.From, Hash: signed.Hash(), Raw: raw, Deployment: u.Deployment}, nil
+}
+
+// Broadcast submits a transaction signed offline, after checking the node is on the chain it was signed for.
+// Deployments are recorded in the registry once submitted.
+func (c *Contract) Broadcast(s *SignedTx) (*types.Transaction, error) {
+	return c.BroadcastContext(context.Background(), s)
+}
+
+// BroadcastContext is like `Broadcast` but every node call is bound to the given context.
+func (c *Contract) BroadcastContext(ctx context.Context, s *SignedTx) (*types.Transaction, error) {
+	tx, err := s.Transaction()
+	if err != nil {
+		log.Err(err).Msg("unable to broadcast transaction")
+		return nil, err
+	}
+
+	chainID, err := c.sendingChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if chainID.Uint64() != s.ChainID {
+		err := fmt.Errorf("%w: transaction is signed for chain %d, node is on chain %d", ErrChainMismatch, s.ChainID, chainID)
+		log.Err(err).Msg("refusing to broadcast transaction")
+		return nil, err
+	}
+
+	if err := c.Client.SendTransaction(ctx, tx); err != nil {
+		log.Err(err).Msg("unable to broadcast transaction")
+		return nil, err
+	}
+
+	if d := s.Deployment; d != nil {
+		c.recordSubmitted(ctx, d.Name, string(d.ABI), d.Bin, s.From, d.Address, tx, nil)
+	}
+
+	return tx, nil
+}
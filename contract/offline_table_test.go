@@ -0,0 +1,127 @@
+package contract_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/goldcoin"
+	"github.com/gopherine/evmos-conploy/signer"
+)
+
+// A test function that tests building a deployment online, signing it offline and broadcasting it.
+func (ts *TableSuite) TestOfflineSigning() {
+	newMock := func(chainID int64) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(chainID), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(100)}, nil).AnyTimes()
+		m.EXPECT().SuggestGasTipCap(gomock.Any()).Return(big.NewInt(7), nil).AnyTimes()
+		m.EXPECT().PendingNonceAt(gomock.Any(), testAddr).Return(uint64(4), nil).AnyTimes()
+		m.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(uint64(900000), nil).AnyTimes()
+
+		return m
+	}
+
+	// the online machine has no signer, only the address of the offline one
+	online := contract.NewContract(newMock(34), contract.WithRegistry(ts.Registry))
+
+	unsigned, err := online.BuildDeploy(testAddr, contract.GoldcoinArtifact())
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), uint64(34), unsigned.ChainID)
+	assert.Nil(ts.T(), unsigned.To)
+	assert.Equal(ts.T(), uint64(4), unsigned.Nonce)
+	assert.Nil(ts.T(), unsigned.GasPrice)
+	assert.Equal(ts.T(), big.NewInt(7), unsigned.GasTipCap)
+	assert.Equal(ts.T(), crypto.CreateAddress(testAddr, 4), unsigned.Deployment.Address)
+	assert.Equal(ts.T(), common.FromHex(goldcoin.GoldcoinBin), []byte(unsigned.Data))
+
+	ts.Run("Round trips through JSON", func() {
+		data, err := json.Marshal(unsigned)
+		ts.Require().NoError(err)
+
+		parsed, err := contract.ParseUnsignedTx(data)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), unsigned, parsed)
+
+		_, err = contract.ParseUnsignedTx([]byte(`{"chainId": 34, "gas": 21000, "gasPrice": 1, "maxFeePerGas": 2, "maxPriorityFeePerGas": 1}`))
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidTransaction)
+
+		_, err = contract.ParseUnsignedTx([]byte(`{"chainId": 34, "gasPrice": 1, "fee": 2}`))
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidTransaction)
+	})
+
+	ts.Run("Only signs with the account it was built for", func() {
+		other, err := signer.FromHex("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		ts.Require().NoError(err)
+
+		_, err = contract.Sign(unsigned, other)
+		assert.ErrorIs(ts.T(), err, contract.ErrWrongSigner)
+	})
+
+	signed, err := contract.Sign(unsigned, testSigner)
+	ts.Require().NoError(err)
+
+	ts.Run("Refuses to broadcast to another chain", func() {
+		_, err := contract.NewContract(newMock(9001)).Broadcast(signed)
+		assert.ErrorIs(ts.T(), err, contract.ErrChainMismatch)
+	})
+
+	ts.Run("Broadcasts the signed transaction and records the deployment", func() {
+		var sent *types.Transaction
+		m := newMock(34)
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			sent = tx
+			return nil
+		})
+
+		c := contract.NewContract(m, contract.WithRegistry(ts.Registry))
+
+		tx, err := c.Broadcast(signed)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), signed.Hash, tx.Hash())
+		assert.Equal(ts.T(), signed.Hash, sent.Hash())
+		assert.Equal(ts.T(), big.NewInt(34), sent.ChainId())
+
+		rec, err := ts.Registry.Latest(34, contract.GoldcoinName)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), unsigned.Deployment.Address, rec.Address)
+		assert.Equal(ts.T(), testAddr, rec.Deployer)
+	})
+
+	ts.Run("Refuses deployments recorded at another address", func() {
+		moved := *signed
+		moved.Deployment = &contract.Deployment{Name: contract.GoldcoinName, Address: crypto.CreateAddress(testAddr, 5)}
+
+		_, err := contract.NewContract(newMock(34), contract.WithRegistry(ts.Registry)).Broadcast(&moved)
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidTransaction)
+
+		call, err := contract.Sign(&contract.UnsignedTx{ChainID: 34, From: testAddr, To: &holderAddr, Nonce: 4, Gas: 21000, GasPrice: big.NewInt(1)}, testSigner)
+		ts.Require().NoError(err)
+		call.Deployment = unsigned.Deployment
+
+		_, err = contract.NewContract(newMock(34), contract.WithRegistry(ts.Registry)).Broadcast(call)
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidTransaction)
+	})
+
+	ts.Run("Reads raw transactions signed by other tools", func() {
+		parsed, err := contract.ParseSignedTx([]byte(hexutil.Encode(signed.Raw) + "\n"))
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), &contract.SignedTx{ChainID: 34, From: testAddr, Hash: signed.Hash, Raw: signed.Raw}, parsed)
+
+		tampered := *signed
+		tampered.From = holderAddr
+		data, err := json.Marshal(&tampered)
+		ts.Require().NoError(err)
+
+		_, err = contract.ParseSignedTx(data)
+		assert.ErrorIs(ts.T(), err, contract.ErrInvalidTransaction)
+	})
+}
@@ -0,0 +1,167 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/rs/zerolog/log"
+)
+
+// ReplacementBump is the percentage the fees of a pending transaction are raised by to replace it, the minimum
+// bump a geth based transaction pool accepts for a transaction at the same nonce.
+const ReplacementBump = 10
+
+// SpeedUp resubmits a pending transaction of the owner address with the same nonce, recipient, value and data
+// and fees raised enough for the node to replace it, the current suggested fees are used when higher. Only one of
+// the two can be mined, `WaitAnyMined` tells which. Transactions already mined fail with `ErrNotPending`.
+func (c *Contract) SpeedUp(txHash common.Hash) (*types.Transaction, error) {
+	return c.SpeedUpContext(context.Background(), txHash)
+}
+
+// SpeedUpContext is like `SpeedUp` but every node call is bound to the given context.
+func (c *Contract) SpeedUpContext(ctx context.Context, txHash common.Hash) (*types.Transaction, error) {
+	return c.replace(ctx, txHash, func(from common.Address, old *types.Transaction) (*common.Address, *big.Int, uint64, []byte) {
+		return old.To(), old.Value(), old.Gas(), old.Data()
+	})
+}
+
+// Cancel replaces a pending transaction of the owner address with a transfer of nothing to itself at the same
+// nonce and with raised fees like `SpeedUp`, so the original is never executed unless it is mined first.
+func (c *Contract) Cancel(txHash common.Hash) (*types.Transaction, error) {
+	return c.CancelContext(context.Background(), txHash)
+}
+
+// CancelContext is like `Cancel` but every node call is bound to the given context.
+func (c *Contract) CancelContext(ctx context.Context, txHash common.Hash) (*types.Transaction, error) {
+	return c.replace(ctx, txHash, func(from common.Address, _ *types.Transaction) (*common.Address, *big.Int, uint64, []byte) {
+		return &from, new(big.Int), params.TxGas, nil
+	})
+}
+
+// replace sends the transaction built by call at the nonce of the pending transaction, call returns its
+// recipient, value, gas limit and data.
+func (c *Contract) replace(ctx context.Context, txHash common.Hash, call func(from common.Address, old *types.Transaction) (*common.Address, *big.Int, uint64, []byte)) (*types.Transaction, error) {
+	if c.Signer == nil {
+		log.Err(ErrNoSigner).Msg("unable to replace transaction")
+		return nil, ErrNoSigner
+	}
+
+	chainID, err := c.sendingChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	old, pending, err := c.Client.TransactionByHash(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		err = fmt.Errorf("transaction %s: %w", txHash.Hex(), err)
+		log.Err(err).Msg("unable to replace transaction")
+		return nil, err
+	} else if err != nil {
+		log.Err(err).Msg("unable to get transaction")
+		return nil, err
+	}
+
+	if !pending {
+		err := fmt.Errorf("%w: %s is already mined", ErrNotPending, txHash.Hex())
+		log.Err(err).Msg("unable to replace transaction")
+		return nil, err
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), old)
+	if err != nil {
+		log.Err(err).Msg("unable to recover transaction sender")
+		return nil, err
+	}
+
+	if from != c.Signer.Address() {
+		err := fmt.Errorf("%w: %s is sent by %s, not %s", ErrWrongSigner, txHash.Hex(), from.Hex(), c.Signer.Address().Hex())
+		log.Err(err).Msg("unable to replace transaction")
+		return nil, err
+	}
+
+	current := &bind.TransactOpts{}
+	if err := c.setFees(ctx, current); err != nil {
+		return nil, err
+	}
+
+	to, value, gas, data := call(from, old)
+	tx := replacement(chainID, old, current, to, value, gas, data)
+
+	signed, err := c.Signer.SignTx(tx, chainID)
+	if err != nil {
+		log.Err(err).Msg("unable to sign transaction")
+		return nil, err
+	}
+
+	if err := c.Client.SendTransaction(ctx, signed); err != nil {
+		if IsNonceError(err) {
+			// mined while the replacement was built
+			err = fmt.Errorf("%w: %s: %v", ErrNotPending, txHash.Hex(), err)
+		}
+
+		log.Err(err).Msg("unable to send replacement transaction")
+
+		return nil, err
+	}
+
+	log.Info().Msgf("%s replaced by %s at nonce %d", txHash.Hex(), signed.Hash().Hex(), signed.Nonce())
+
+	return signed, nil
+}
+
+// replacement builds the transaction replacing old, of the same type, with each of its fees bumped by
+// `ReplacementBump` or the current suggested fee when higher.
+func replacement(chainID *big.Int, old *types.Transaction, current *bind.TransactOpts, to *common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+	if old.Type() != types.DynamicFeeTxType {
+		// legacy fees of the current policy, or the fee cap of a dynamic fee chain which is a price it accepts
+		price := maxBig(bumpFee(old.GasPrice()), current.GasPrice, current.GasFeeCap)
+
+		if old.Type() == types.AccessListTxType {
+			return types.NewTx(&types.AccessListTx{
+				ChainID: chainID, Nonce: old.Nonce(), GasPrice: price, Gas: gas, To: to, Value: value, Data: data,
+				AccessList: old.AccessList(),
+			})
+		}
+
+		return types.NewTx(&types.LegacyTx{Nonce: old.Nonce(), GasPrice: price, Gas: gas, To: to, Value: value, Data: data})
+	}
+
+	tipCap := maxBig(bumpFee(old.GasTipCap()), current.GasTipCap)
+	feeCap := maxBig(bumpFee(old.GasFeeCap()), current.GasFeeCap, current.GasPrice, tipCap)
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID: chainID, Nonce: old.Nonce(), GasTipCap: tipCap, GasFeeCap: feeCap, Gas: gas, To: to, Value: value,
+		Data: data, AccessList: old.AccessList(),
+	})
+}
+
+// bumpFee raises the fee by `ReplacementBump` percent, and by at least one as it must be strictly higher.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+ReplacementBump))
+	bumped.Div(bumped, big.NewInt(100))
+
+	if bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, big.NewInt(1))
+	}
+
+	return bumped
+}
+
+// maxBig returns the highest of the values, nil values are ignored.
+func maxBig(first *big.Int, rest ...*big.Int) *big.Int {
+	highest := first
+	for _, v := range rest {
+		if v != nil && v.Cmp(highest) > 0 {
+			highest = v
+		}
+	}
+
+	return new(big.Int).Set(highest)
+}
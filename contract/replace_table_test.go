@@ -0,0 +1,167 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// A test function that tests speeding up and cancelling pending transactions.
+func (ts *TableSuite) TestReplace() {
+	token := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	data := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	chainSigner := types.LatestSignerForChainID(big.NewInt(40))
+
+	dynamic, err := types.SignNewTx(testKey, chainSigner, &types.DynamicFeeTx{
+		ChainID: big.NewInt(40), Nonce: 9, GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(210), Gas: 60000, To: &token, Data: data,
+	})
+	ts.Require().NoError(err)
+
+	legacy, err := types.SignNewTx(testKey, chainSigner, &types.LegacyTx{Nonce: 9, GasPrice: big.NewInt(1000), Gas: 60000, To: &token, Data: data})
+	ts.Require().NoError(err)
+
+	// newMock serves the original transaction as pending on chain 40, the suggested fee cap is tipCap + 200
+	newMock := func(original *types.Transaction, tipCap int64, sent **types.Transaction) *contract.MockIBlockchain {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(40), nil).AnyTimes()
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(100)}, nil).AnyTimes()
+		m.EXPECT().SuggestGasTipCap(gomock.Any()).Return(big.NewInt(tipCap), nil).AnyTimes()
+		m.EXPECT().TransactionByHash(gomock.Any(), original.Hash()).Return(original, true, nil).AnyTimes()
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+			*sent = tx
+			return nil
+		}).AnyTimes()
+
+		return m
+	}
+
+	assertSender := func(tx *types.Transaction) {
+		from, err := types.Sender(chainSigner, tx)
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), testAddr, from)
+	}
+
+	ts.Run("Speeds up with every fee bumped", func() {
+		var sent *types.Transaction
+		c := contract.NewContract(newMock(dynamic, 7, &sent), contract.WithSigner(testSigner))
+
+		tx, err := c.SpeedUp(dynamic.Hash())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), sent, tx)
+		assert.Equal(ts.T(), uint8(types.DynamicFeeTxType), tx.Type())
+		assert.Equal(ts.T(), uint64(9), tx.Nonce())
+		assert.Equal(ts.T(), big.NewInt(11), tx.GasTipCap())
+		assert.Equal(ts.T(), big.NewInt(231), tx.GasFeeCap())
+		assert.Equal(ts.T(), &token, tx.To())
+		assert.Equal(ts.T(), data, tx.Data())
+		assert.Equal(ts.T(), uint64(60000), tx.Gas())
+		assertSender(tx)
+	})
+
+	ts.Run("Speeds up with the suggested fees when higher", func() {
+		var sent *types.Transaction
+		c := contract.NewContract(newMock(dynamic, 50, &sent), contract.WithSigner(testSigner))
+
+		tx, err := c.SpeedUp(dynamic.Hash())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), big.NewInt(50), tx.GasTipCap())
+		assert.Equal(ts.T(), big.NewInt(250), tx.GasFeeCap())
+	})
+
+	ts.Run("Speeds up legacy transactions with a legacy one", func() {
+		var sent *types.Transaction
+		c := contract.NewContract(newMock(legacy, 7, &sent), contract.WithSigner(testSigner))
+
+		tx, err := c.SpeedUp(legacy.Hash())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), uint8(types.LegacyTxType), tx.Type())
+		assert.Equal(ts.T(), big.NewInt(1100), tx.GasPrice())
+		assertSender(tx)
+	})
+
+	ts.Run("Cancels with a transfer of nothing to itself", func() {
+		var sent *types.Transaction
+		c := contract.NewContract(newMock(dynamic, 7, &sent), contract.WithSigner(testSigner))
+
+		tx, err := c.Cancel(dynamic.Hash())
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), uint64(9), tx.Nonce())
+		assert.Equal(ts.T(), &testAddr, tx.To())
+		assert.Equal(ts.T(), big.NewInt(0), tx.Value())
+		assert.Empty(ts.T(), tx.Data())
+		assert.Equal(ts.T(), uint64(21000), tx.Gas())
+		assert.Equal(ts.T(), big.NewInt(11), tx.GasTipCap())
+		assertSender(tx)
+	})
+
+	ts.Run("Refuses transactions already mined", func() {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(40), nil)
+		m.EXPECT().TransactionByHash(gomock.Any(), dynamic.Hash()).Return(dynamic, false, nil)
+
+		_, err := contract.NewContract(m, contract.WithSigner(testSigner)).Cancel(dynamic.Hash())
+		assert.ErrorIs(ts.T(), err, contract.ErrNotPending)
+	})
+
+	ts.Run("Refuses transactions unknown to the node", func() {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(40), nil)
+		m.EXPECT().TransactionByHash(gomock.Any(), dynamic.Hash()).Return(nil, false, ethereum.NotFound)
+
+		_, err := contract.NewContract(m, contract.WithSigner(testSigner)).SpeedUp(dynamic.Hash())
+		assert.ErrorIs(ts.T(), err, ethereum.NotFound)
+	})
+
+	ts.Run("Refuses transactions of other accounts", func() {
+		otherKey, err := crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		ts.Require().NoError(err)
+		other, err := types.SignNewTx(otherKey, chainSigner, &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1000), Gas: 21000, To: &token})
+		ts.Require().NoError(err)
+
+		var sent *types.Transaction
+		_, err = contract.NewContract(newMock(other, 7, &sent), contract.WithSigner(testSigner)).SpeedUp(other.Hash())
+		assert.ErrorIs(ts.T(), err, contract.ErrWrongSigner)
+		assert.Nil(ts.T(), sent)
+	})
+
+	ts.Run("Reports the original mined while replacing it", func() {
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(40), nil)
+		m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(100)}, nil)
+		m.EXPECT().SuggestGasTipCap(gomock.Any()).Return(big.NewInt(7), nil)
+		m.EXPECT().TransactionByHash(gomock.Any(), dynamic.Hash()).Return(dynamic, true, nil)
+		m.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).Return(errors.New("nonce too low"))
+
+		_, err := contract.NewContract(m, contract.WithSigner(testSigner)).SpeedUp(dynamic.Hash())
+		assert.ErrorIs(ts.T(), err, contract.ErrNotPending)
+	})
+
+	ts.Run("Waits for whichever version is mined", func() {
+		replacement := common.HexToHash("0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b")
+
+		m := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		m.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported"))
+		gomock.InOrder(
+			m.EXPECT().TransactionReceipt(gomock.Any(), dynamic.Hash()).Return(nil, ethereum.NotFound),
+			m.EXPECT().TransactionReceipt(gomock.Any(), replacement).Return(nil, ethereum.NotFound),
+			m.EXPECT().TransactionReceipt(gomock.Any(), dynamic.Hash()).Return(nil, ethereum.NotFound),
+			m.EXPECT().TransactionReceipt(gomock.Any(), replacement).Return(&types.Receipt{
+				TxHash: replacement, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10),
+			}, nil),
+		)
+
+		receipt, err := contract.NewContract(m).WaitAnyMined([]common.Hash{dynamic.Hash(), replacement}, contract.WaitOpts{PollInterval: time.Millisecond})
+		ts.Require().NoError(err)
+		assert.Equal(ts.T(), replacement, receipt.TxHash)
+	})
+}
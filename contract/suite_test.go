@@ -3,7 +3,6 @@ package contract_test
 import (
 	"context"
 	"math/big"
-	"os"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/core"
@@ -15,6 +14,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
+	"github.com/gopherine/evmos-conploy/signer"
 )
 
 // `TableSuite` is a struct that contains a `suite.Suite` and a `*backends.SimulatedBackend`.
@@ -26,6 +27,7 @@ type TableSuite struct {
 	ClientMock   *contract.MockIBlockchain
 	Ctrl         *gomock.Controller
 	Ctx          context.Context
+	Registry     *registry.Registry
 }
 
 // Creating a test account with a balance of 2e15
@@ -33,6 +35,7 @@ var (
 	testKeyStr  = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
 	testKey, _  = crypto.HexToECDSA(testKeyStr)
 	testAddr    = crypto.PubkeyToAddress(testKey.PublicKey)
+	testSigner  = signer.NewKeySigner(testKey)
 	testBalance = big.NewInt(2e15)
 )
 
@@ -57,7 +60,16 @@ func TestTableSuite(t *testing.T) {
 
 	// Creating a mock instance of the `IBlockchain` interface.
 	clientMock := contract.NewMockIBlockchain(ctrl)
-	contractInstance := contract.NewContract(clientMock)
+
+	// Local registry backing deployment records for this suite run.
+	reg, err := registry.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Close()
+
+	// every case mocks the pending nonce of a fresh node, nonces are not allocated locally across them
+	contractInstance := contract.NewContract(clientMock, contract.WithSigner(testSigner), contract.WithRegistry(reg), contract.WithNonceManager(nil))
 
 	// Creating a mock instance of the `IGoldcoin` interface.
 	goldcoinMock := contract.NewMockIGoldcoin(ctrl)
@@ -69,15 +81,12 @@ func TestTableSuite(t *testing.T) {
 		GoldcoinMock: goldcoinMock,
 		Ctx:          context.Background(),
 		Ctrl:         ctrl,
+		Registry:     reg,
 	})
 }
 
 // Suite init for BDD
 func TestSrc(t *testing.T) {
-	// Setting a mock environment variable for testing.
-	os.Setenv("OWNER_PRIVATEKEY", "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
-	defer os.Unsetenv("OWNER_PRIVATEKEY")
-
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Shared Suite")
 }
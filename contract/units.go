@@ -0,0 +1,112 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/rs/zerolog/log"
+)
+
+// TokenUnits converts between human readable token amounts and the base units the contract works with.
+type TokenUnits struct {
+	Decimals uint8
+	Symbol   string
+}
+
+// TokenUnits reads the decimals and symbol of the token
+func (c *Contract) TokenUnits(instance IGoldcoin) (TokenUnits, error) {
+	return c.TokenUnitsContext(context.Background(), instance)
+}
+
+// TokenUnitsContext is like `TokenUnits` but the token is read with the given context.
+func (c *Contract) TokenUnitsContext(ctx context.Context, instance IGoldcoin) (TokenUnits, error) {
+	opts := &bind.CallOpts{Context: ctx}
+
+	decimals, err := instance.Decimals(opts)
+	if err != nil {
+		log.Err(err).Msg("unable to get token decimals")
+		return TokenUnits{}, err
+	}
+
+	symbol, err := instance.Symbol(opts)
+	if err != nil {
+		log.Err(err).Msg("unable to get token symbol")
+		return TokenUnits{}, err
+	}
+
+	return TokenUnits{Decimals: decimals, Symbol: symbol}, nil
+}
+
+// Parse converts a decimal amount such as `12.5` or `12.5 GLD` to base units, the symbol is optional but must
+// match the token's when given. Failures are returned as `*AmountError`.
+func (u TokenUnits) Parse(s string) (*big.Int, error) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 2 && !strings.EqualFold(fields[1], u.Symbol):
+		return nil, &AmountError{Input: s, Err: fmt.Errorf("%w: unit %s is not %s", ErrInvalidAmount, fields[1], u.Symbol)}
+	case len(fields) == 0 || len(fields) > 2:
+		return nil, &AmountError{Input: s, Err: ErrInvalidAmount}
+	}
+
+	value := fields[0]
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if whole+frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return nil, &AmountError{Input: s, Err: ErrInvalidAmount}
+	}
+
+	// trailing zeros do not change the amount, only significant digits must fit the decimals
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(u.Decimals) {
+		return nil, &AmountError{Input: s, Err: fmt.Errorf("%w: more than %d decimal places", ErrInvalidAmount, u.Decimals)}
+	}
+
+	amount, _ := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(u.Decimals)-len(frac)), 10)
+	if negative && amount.Sign() != 0 {
+		return nil, &AmountError{Input: s, Err: ErrNegativeAmount}
+	}
+
+	return amount, nil
+}
+
+// Format converts an amount in base units to a decimal string followed by the token symbol, eg. `12.5 GLD`.
+func (u TokenUnits) Format(amount *big.Int) string {
+	digits := new(big.Int).Abs(amount).String()
+	if pad := int(u.Decimals) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	point := len(digits) - int(u.Decimals)
+	value := digits[:point]
+
+	if frac := strings.TrimRight(digits[point:], "0"); frac != "" {
+		value += "." + frac
+	}
+
+	if amount.Sign() < 0 {
+		value = "-" + value
+	}
+
+	if u.Symbol == "" {
+		return value
+	}
+
+	return value + " " + u.Symbol
+}
+
+// isDigits reports whether s only holds the digits 0-9, an empty string does.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,128 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// A test function that tests the `TokenUnits` function.
+func (ts *TableSuite) TestTokenUnits() {
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	subtests := []struct {
+		name    string
+		prepare func(m *contract.MockIGoldcoin)
+		want    contract.TokenUnits
+		wantErr bool
+	}{
+		{
+			name: "Reads decimals and symbol",
+			prepare: func(m *contract.MockIGoldcoin) {
+				m.EXPECT().Decimals(opts).Return(uint8(18), nil)
+				m.EXPECT().Symbol(opts).Return("GLD", nil)
+			},
+			want: contract.TokenUnits{Decimals: 18, Symbol: "GLD"},
+		},
+		{
+			name: "Error Decimals",
+			prepare: func(m *contract.MockIGoldcoin) {
+				m.EXPECT().Decimals(opts).Return(uint8(0), errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error Symbol",
+			prepare: func(m *contract.MockIGoldcoin) {
+				m.EXPECT().Decimals(opts).Return(uint8(18), nil)
+				m.EXPECT().Symbol(opts).Return("", errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			tt.prepare(ts.GoldcoinMock)
+
+			units, err := ts.Contract.TokenUnits(ts.GoldcoinMock)
+			if tt.wantErr {
+				assert.Error(ts.T(), err)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), tt.want, units)
+		})
+	}
+}
+
+// A test function that tests conversion of human readable amounts to base units.
+func (ts *TableSuite) TestTokenUnitsParse() {
+	units := contract.TokenUnits{Decimals: 18, Symbol: "GLD"}
+
+	subtests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "Whole amount", input: "12", want: "12000000000000000000"},
+		{name: "Decimal amount", input: "12.5", want: "12500000000000000000"},
+		{name: "Amount with symbol", input: "12.5 GLD", want: "12500000000000000000"},
+		{name: "Amount with lower case symbol", input: "12.5 gld", want: "12500000000000000000"},
+		{name: "Leading decimal point", input: ".5", want: "500000000000000000"},
+		{name: "Smallest unit", input: "0.000000000000000001", want: "1"},
+		{name: "Trailing zeros past decimals", input: "1.0000000000000000000", want: "1000000000000000000"},
+		{name: "Error too many decimal places", input: "0.0000000000000000001", wantErr: contract.ErrInvalidAmount},
+		{name: "Error other symbol", input: "12.5 ETH", wantErr: contract.ErrInvalidAmount},
+		{name: "Error not a number", input: "twelve", wantErr: contract.ErrInvalidAmount},
+		{name: "Error lone decimal point", input: ".", wantErr: contract.ErrInvalidAmount},
+		{name: "Error empty amount", input: "", wantErr: contract.ErrInvalidAmount},
+		{name: "Error negative amount", input: "-1.5", wantErr: contract.ErrNegativeAmount},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			amount, err := units.Parse(tt.input)
+			if tt.wantErr != nil {
+				var amountErr *contract.AmountError
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				assert.ErrorAs(ts.T(), err, &amountErr)
+				return
+			}
+
+			assert.NoError(ts.T(), err)
+			assert.Equal(ts.T(), tt.want, amount.String())
+		})
+	}
+}
+
+// A test function that tests formatting of base unit amounts.
+func (ts *TableSuite) TestTokenUnitsFormat() {
+	subtests := []struct {
+		name   string
+		units  contract.TokenUnits
+		amount string
+		want   string
+	}{
+		{name: "Decimal amount", units: contract.TokenUnits{Decimals: 18, Symbol: "GLD"}, amount: "12500000000000000000", want: "12.5 GLD"},
+		{name: "Amount below one", units: contract.TokenUnits{Decimals: 18, Symbol: "GLD"}, amount: "2000000000000000", want: "0.002 GLD"},
+		{name: "Smallest unit", units: contract.TokenUnits{Decimals: 18, Symbol: "GLD"}, amount: "1", want: "0.000000000000000001 GLD"},
+		{name: "Zero", units: contract.TokenUnits{Decimals: 18, Symbol: "GLD"}, amount: "0", want: "0 GLD"},
+		{name: "No decimals", units: contract.TokenUnits{Symbol: "GLD"}, amount: "42", want: "42 GLD"},
+		{name: "No symbol", units: contract.TokenUnits{Decimals: 2}, amount: "-1234", want: "-12.34"},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			amount, _ := new(big.Int).SetString(tt.amount, 10)
+			assert.Equal(ts.T(), tt.want, tt.units.Format(amount))
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultWaitTimeout is how long `WaitMined` waits when no timeout is given
+	DefaultWaitTimeout = 2 * time.Minute
+	// DefaultPollInterval is how often `WaitMined` polls for the receipt when no interval is given
+	DefaultPollInterval = 2 * time.Second
+)
+
+// ErrTxReverted is returned when a transaction was mined but its execution failed
+var ErrTxReverted = errors.New("transaction reverted")
+
+// RevertError describes a mined transaction whose execution failed, it matches `ErrTxReverted` with `errors.Is`.
+type RevertError struct {
+	Receipt *types.Receipt
+	// Reason is the decoded revert reason, empty when the node did not return one
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	msg := fmt.Sprintf("%s: tx %s status %d gas used %d", ErrTxReverted, e.Receipt.TxHash.Hex(), e.Receipt.Status, e.Receipt.GasUsed)
+	if e.Reason != "" {
+		msg += fmt.Sprintf(" reason %q", e.Reason)
+	}
+
+	return msg
+}
+
+func (e *RevertError) Unwrap() error {
+	return ErrTxReverted
+}
+
+// WaitOpts configures how `WaitMined` waits for a transaction.
+type WaitOpts struct {
+	// Confirmations is the number of blocks, including the one the transaction is mined in, to wait for
+	Confirmations uint64
+	// Timeout bounds the whole wait, defaults to `DefaultWaitTimeout`
+	Timeout time.Duration
+	// PollInterval is how often the receipt is polled for, defaults to `DefaultPollInterval`
+	PollInterval time.Duration
+}
+
+// dataError is implemented by rpc errors carrying the revert data of a failed call
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// WaitMined blocks until the transaction is mined with the requested number of confirmations. New heads are
+// followed through `SubscribeNewHead` when the node supports it and the receipt is polled for otherwise.
+// A reverted transaction returns its receipt along with a `*RevertError`.
+func (c *Contract) WaitMined(txHash common.Hash, opts WaitOpts) (*types.Receipt, error) {
+	return c.WaitMinedContext(context.Background(), txHash, opts)
+}
+
+// WaitMinedContext is like `WaitMined` but stops waiting as soon as the given context is done, the wait
+// timeout applies on top of any deadline the context already has.
+func (c *Contract) WaitMinedContext(ctx context.Context, txHash common.Hash, opts WaitOpts) (*types.Receipt, error) {
+	return c.WaitAnyMinedContext(ctx, []common.Hash{txHash}, opts)
+}
+
+// WaitAnyMined is like `WaitMined` but returns the receipt of the first of the transactions mined, meant for
+// transactions replacing each other at the same nonce of which only one can be mined. The transaction hash of
+// the receipt tells which one it is.
+func (c *Contract) WaitAnyMined(txHashes []common.Hash, opts WaitOpts) (*types.Receipt, error) {
+	return c.WaitAnyMinedContext(context.Background(), txHashes, opts)
+}
+
+// WaitAnyMinedContext is like `WaitAnyMined` but stops waiting as soon as the given context is done.
+func (c *Contract) WaitAnyMinedContext(ctx context.Context, txHashes []common.Hash, opts WaitOpts) (*types.Receipt, error) {
+	timeout, interval := opts.Timeout, opts.PollInterval
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	heads := make(chan *types.Header, 1)
+
+	var subErr <-chan error
+
+	// http only nodes do not support subscriptions, polling below covers them
+	if sub, err := c.Client.SubscribeNewHead(ctx, heads); err == nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, txHash := range txHashes {
+			receipt, err := c.confirmedReceipt(ctx, txHash, opts.Confirmations)
+			if err != nil {
+				return nil, err
+			}
+
+			if receipt != nil {
+				if receipt.Status == types.ReceiptStatusFailed {
+					return receipt, &RevertError{Receipt: receipt, Reason: c.revertReason(ctx, txHash, receipt)}
+				}
+
+				return receipt, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for transaction %s: %w", hashList(txHashes), ctx.Err())
+		case err := <-subErr:
+			log.Warn().Err(err).Msg("new head subscription dropped, falling back to polling")
+			subErr = nil
+		case <-heads:
+		case <-ticker.C:
+		}
+	}
+}
+
+// hashList joins the hashes of transactions waited for, for error messages.
+func hashList(txHashes []common.Hash) string {
+	hexes := make([]string, len(txHashes))
+	for i, txHash := range txHashes {
+		hexes[i] = txHash.Hex()
+	}
+
+	return strings.Join(hexes, " or ")
+}
+
+// confirmedReceipt returns the receipt of the transaction once it has enough confirmations, nil otherwise.
+func (c *Contract) confirmedReceipt(ctx context.Context, txHash common.Hash, confirmations uint64) (*types.Receipt, error) {
+	receipt, err := c.Client.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	} else if err != nil {
+		log.Err(err).Msg("unable to get transaction reciept")
+		return nil, err
+	}
+
+	if confirmations <= 1 {
+		return receipt, nil
+	}
+
+	head, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Err(err).Msg("unable to get latest header")
+		return nil, err
+	}
+
+	mined := new(big.Int).Sub(head.Number, receipt.BlockNumber).Uint64() + 1
+	if head.Number.Cmp(receipt.BlockNumber) < 0 || mined < confirmations {
+		return nil, nil
+	}
+
+	return receipt, nil
+}
+
+// revertReason replays the transaction on the state it was mined on top of and decodes the revert reason,
+// an empty reason is returned when it cannot be determined.
+func (c *Contract) revertReason(ctx context.Context, txHash common.Hash, receipt *types.Receipt) string {
+	tx, _, err := c.Client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		log.Err(err).Msg("unable to get reverted transaction")
+		return ""
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		log.Err(err).Msg("unable to recover reverted transaction sender")
+		return ""
+	}
+
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+
+	result, err := c.Client.CallContract(ctx, msg, parent)
+	if err != nil {
+		var dErr dataError
+		if !errors.As(err, &dErr) {
+			return err.Error()
+		}
+
+		hexData, ok := dErr.ErrorData().(string)
+		if !ok {
+			return err.Error()
+		}
+
+		result = common.FromHex(hexData)
+	}
+
+	reason, err := abi.UnpackRevert(result)
+	if err != nil {
+		return ""
+	}
+
+	return reason
+}
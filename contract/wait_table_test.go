@@ -0,0 +1,149 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+)
+
+// revertDataError mimics the rpc error returned by a node for a reverted call
+type revertDataError struct {
+	data string
+}
+
+func (e revertDataError) Error() string          { return "execution reverted" }
+func (e revertDataError) ErrorData() interface{} { return e.data }
+
+// packRevert encodes the reason the same way solidity's `Error(string)` does
+func packRevert(reason string) string {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, _ := abi.Arguments{{Type: stringType}}.Pack(reason)
+
+	return hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...))
+}
+
+// A test function that tests the `WaitMined` function.
+func (ts *TableSuite) TestWaitMined() {
+	txHash := common.HexToHash("0x3a33a98d6eb8d2b0e2a0fd1f4cf9d071992cbb0cc4e0e9887711dde505259e9b")
+	to := common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+
+	signedTx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    1,
+		To:       &to,
+		Gas:      60000,
+		GasPrice: big.NewInt(1000),
+		Value:    big.NewInt(0),
+		Data:     []byte{0xa9, 0x05, 0x9c, 0xbb},
+	}), types.LatestSignerForChainID(big.NewInt(1)), testKey)
+	ts.Require().NoError(err)
+
+	receipt := func(status uint64) *types.Receipt {
+		return &types.Receipt{TxHash: txHash, Status: status, GasUsed: 50000, BlockNumber: big.NewInt(10)}
+	}
+
+	subtests := []struct {
+		name       string
+		opts       contract.WaitOpts
+		prepare    func(m *contract.MockIBlockchain)
+		wantErr    error
+		wantReason string
+	}{
+		{
+			name: "Mined transaction",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt(types.ReceiptStatusSuccessful), nil)
+			},
+		},
+		{
+			name: "Polls until transaction is mined",
+			prepare: func(m *contract.MockIBlockchain) {
+				gomock.InOrder(
+					m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, ethereum.NotFound).Times(2),
+					m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt(types.ReceiptStatusSuccessful), nil),
+				)
+			},
+		},
+		{
+			name: "Waits for confirmations",
+			opts: contract.WaitOpts{Confirmations: 3},
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt(types.ReceiptStatusSuccessful), nil).Times(2)
+				gomock.InOrder(
+					m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(11)}, nil),
+					m.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(12)}, nil),
+				)
+			},
+		},
+		{
+			name: "Error reverted transaction with reason",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt(types.ReceiptStatusFailed), nil)
+				m.EXPECT().TransactionByHash(gomock.Any(), txHash).Return(signedTx, false, nil)
+				m.EXPECT().CallContract(gomock.Any(), gomock.Any(), big.NewInt(9)).DoAndReturn(
+					func(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+						assert.Equal(ts.T(), testAddr, call.From)
+						assert.Equal(ts.T(), &to, call.To)
+						return nil, revertDataError{data: packRevert("ERC20: transfer amount exceeds balance")}
+					})
+			},
+			wantErr:    contract.ErrTxReverted,
+			wantReason: "ERC20: transfer amount exceeds balance",
+		},
+		{
+			name: "Error reciept",
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "Error timeout",
+			opts: contract.WaitOpts{Timeout: 20 * time.Millisecond},
+			prepare: func(m *contract.MockIBlockchain) {
+				m.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, ethereum.NotFound).MinTimes(1)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range subtests {
+		ts.Run(tt.name, func() {
+			// http only node, receipts are polled for
+			ts.ClientMock.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported"))
+			tt.prepare(ts.ClientMock)
+
+			opts := tt.opts
+			opts.PollInterval = time.Millisecond
+
+			r, err := ts.Contract.WaitMined(txHash, opts)
+
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(ts.T(), err)
+				assert.Equal(ts.T(), txHash, r.TxHash)
+			case tt.wantReason != "":
+				var revertErr *contract.RevertError
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+				assert.ErrorAs(ts.T(), err, &revertErr)
+				assert.Equal(ts.T(), tt.wantReason, revertErr.Reason)
+				assert.Equal(ts.T(), uint64(50000), revertErr.Receipt.GasUsed)
+			case errors.Is(tt.wantErr, context.DeadlineExceeded):
+				assert.ErrorIs(ts.T(), err, tt.wantErr)
+			default:
+				assert.Error(ts.T(), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/rs/zerolog/log"
+
+	"github.com/gopherine/evmos-conploy/goldcoin"
+)
+
+const (
+	// DefaultRetryInterval is the first delay before resubscribing when `Watch` has no subscription
+	DefaultRetryInterval = time.Second
+	// maxRetryInterval caps the exponential backoff between resubscriptions
+	maxRetryInterval = 30 * time.Second
+	// seenDepth is the number of blocks behind the cursor delivered events are remembered for
+	seenDepth = 64
+)
+
+// WatchQuery selects the token events streamed by `Watch`.
+type WatchQuery struct {
+	// From matches events sent by, or approvals given by, any of the addresses, any sender when empty
+	From []common.Address
+	// To matches events recieved by, or approvals given to, any of the addresses, any reciever when empty
+	To []common.Address
+	// MinValue drops events with a smaller value, no minimum when nil
+	MinValue *big.Int
+	// Events lists the event names to stream, both transfers and approvals when empty
+	Events []string
+	// FromBlock defaults to the block after the latest one, only new events are streamed
+	FromBlock *uint64
+	// PollInterval is how often logs are polled for without a subscription, defaults to `DefaultPollInterval`
+	PollInterval time.Duration
+	// RetryInterval is the first delay before resubscribing, defaults to `DefaultRetryInterval`
+	RetryInterval time.Duration
+}
+
+// watcher keeps the state of a running `Watch`, the cursor is the first block whose events may not all have
+// been delivered yet.
+type watcher struct {
+	client   IBlockchain
+	filterer *goldcoin.GoldcoinFilterer
+	filter   eventFilter
+	minValue *big.Int
+	fn       func(Event) error
+	cursor   uint64
+	seen     map[eventKey]uint64
+	// pruned is the cursor the delivered events were last pruned at
+	pruned uint64
+	// fnErr is the error returned by fn, it ends the watch
+	fnErr error
+}
+
+// Watch streams new token events selected by the query to fn until fn returns an error. Events are followed
+// through log subscriptions, when the node is http only or the subscription drops logs are polled for while
+// resubscribing with an exponential backoff. Every event is delivered once, the blocks missed while
+// disconnected are queried again on reconnection.
+func (c *Contract) Watch(query WatchQuery, fn func(Event) error) error {
+	return c.WatchContext(context.Background(), query, fn)
+}
+
+// WatchContext is like `Watch` but also stops, with the context error, once the context is done.
+func (c *Contract) WatchContext(ctx context.Context, query WatchQuery, fn func(Event) error) error {
+	rec, err := c.latestDeployment(ctx, GoldcoinName)
+	if err != nil {
+		return err
+	}
+
+	filterer, err := goldcoin.NewGoldcoinFilterer(rec.Address, c.Client)
+	if err != nil {
+		log.Err(err).Msg("unable to bind goldcoin filterer")
+		return err
+	}
+
+	w := &watcher{
+		client:   c.Client,
+		filterer: filterer,
+		filter:   eventFilter{from: query.From, to: query.To, events: query.Events},
+		minValue: query.MinValue,
+		fn:       fn,
+		seen:     make(map[eventKey]uint64),
+	}
+
+	if query.FromBlock != nil {
+		w.cursor = *query.FromBlock
+	} else {
+		head, err := c.Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Err(err).Msg("unable to get latest header")
+			return err
+		}
+
+		w.cursor = head.Number.Uint64() + 1
+	}
+
+	w.pruned = w.cursor
+
+	poll, retry := query.PollInterval, query.RetryInterval
+	if poll <= 0 {
+		poll = DefaultPollInterval
+	}
+
+	if retry <= 0 {
+		retry = DefaultRetryInterval
+	}
+
+	backoff := retry
+
+	for {
+		transfers, approvals, sub, err := w.subscribe(ctx)
+		if err == nil {
+			backoff = retry
+
+			err = w.follow(ctx, transfers, approvals, sub)
+			sub.Unsubscribe()
+
+			if w.fnErr != nil {
+				return w.fnErr
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			log.Warn().Err(err).Msg("log subscription dropped, reconnecting")
+		} else {
+			log.Debug().Err(err).Msg("log subscription unavailable")
+		}
+
+		// events keep being polled for until the next subscription attempt
+		if err := w.pollFor(ctx, poll, backoff); err != nil {
+			return err
+		}
+
+		if backoff *= 2; backoff > maxRetryInterval {
+			backoff = maxRetryInterval
+		}
+	}
+}
+
+// subscribe subscribes to the events of the filter, the returned subscription ends both event streams.
+func (w *watcher) subscribe(ctx context.Context) (chan *goldcoin.GoldcoinTransfer, chan *goldcoin.GoldcoinApproval, event.Subscription, error) {
+	opts := &bind.WatchOpts{Context: ctx}
+	transfers := make(chan *goldcoin.GoldcoinTransfer)
+	approvals := make(chan *goldcoin.GoldcoinApproval)
+
+	var subs []event.Subscription
+
+	if w.filter.wants(EventTransfer) {
+		sub, err := w.filterer.WatchTransfer(opts, transfers, w.filter.from, w.filter.to)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if w.filter.wants(EventApproval) {
+		sub, err := w.filterer.WatchApproval(opts, approvals, w.filter.from, w.filter.to)
+		if err != nil {
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+
+			return nil, nil, nil, err
+		}
+
+		subs = append(subs, sub)
+	}
+
+	// a single subscription failing on either stream ends both
+	sub := event.NewSubscription(func(quit <-chan struct{}) error {
+		defer func() {
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+		}()
+
+		errs := make(chan error, len(subs))
+		for _, s := range subs {
+			go func(s event.Subscription) {
+				if err, ok := <-s.Err(); ok {
+					errs <- err
+				}
+			}(s)
+		}
+
+		select {
+		case err := <-errs:
+			return err
+		case <-quit:
+			return nil
+		}
+	})
+
+	return transfers, approvals, sub, nil
+}
+
+// follow delivers the events missed since the cursor and then the subscribed ones until the subscription or
+// the watch ends.
+func (w *watcher) follow(ctx context.Context, transfers chan *goldcoin.GoldcoinTransfer, approvals chan *goldcoin.GoldcoinApproval, sub event.Subscription) error {
+	// subscribed events arriving meanwhile are buffered by the subscription and deduplicated on delivery
+	if err := w.catchUp(ctx); err != nil {
+		return err
+	}
+
+	for {
+		var ev Event
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case t := <-transfers:
+			ev = transferEvent(t)
+			ev.removed = t.Raw.Removed
+		case a := <-approvals:
+			ev = approvalEvent(a)
+			ev.removed = a.Raw.Removed
+		}
+
+		if err := w.deliver(ev); err != nil {
+			return err
+		}
+
+		// later events of the same block may still arrive, they are deduplicated if queried again
+		if ev.BlockNumber > w.cursor {
+			w.cursor = ev.BlockNumber
+			w.prune()
+		}
+	}
+}
+
+// pollFor polls for new events every interval for the given duration. Node errors are logged and polling goes on,
+// only the end of the watch stops it early.
+func (w *watcher) pollFor(ctx context.Context, interval, duration time.Duration) error {
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.catchUp(ctx); err != nil {
+			if w.fnErr != nil {
+				return w.fnErr
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			log.Warn().Err(err).Msg("unable to poll for events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// catchUp delivers the events from the cursor up to the latest block and moves the cursor past it.
+func (w *watcher) catchUp(ctx context.Context) error {
+	head, err := w.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	latest := head.Number.Uint64()
+
+	for from := w.cursor; from <= latest; from += DefaultChunkSize {
+		to := from + DefaultChunkSize - 1
+		if to > latest {
+			to = latest
+		}
+
+		events, err := fetchEvents(ctx, w.filterer, []eventFilter{w.filter}, from, to)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			if err := w.deliver(ev); err != nil {
+				return err
+			}
+		}
+
+		w.cursor = to + 1
+	}
+
+	w.prune()
+
+	return nil
+}
+
+// prune forgets the delivered events more than `seenDepth` blocks behind the cursor once the cursor moved that far
+// since the last prune, so a subscription followed for days remembers a bounded number of events.
+func (w *watcher) prune() {
+	if w.cursor < w.pruned+seenDepth {
+		return
+	}
+
+	for key, block := range w.seen {
+		if block+seenDepth < w.cursor {
+			delete(w.seen, key)
+		}
+	}
+
+	w.pruned = w.cursor
+}
+
+// deliver hands the event to fn unless it was already delivered, removed by a reorg or below the minimum value.
+func (w *watcher) deliver(ev Event) error {
+	if _, seen := w.seen[ev.key()]; seen || ev.removed || (w.minValue != nil && ev.Value.Cmp(w.minValue) < 0) {
+		return nil
+	}
+
+	w.seen[ev.key()] = ev.BlockNumber
+
+	if err := w.fn(ev); err != nil {
+		w.fnErr = err
+		return err
+	}
+
+	return nil
+}
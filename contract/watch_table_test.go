@@ -0,0 +1,253 @@
+package contract_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+// fakeNode serves headers and logs to a watcher while the test adds new blocks
+type fakeNode struct {
+	mu   sync.Mutex
+	head uint64
+	logs []types.Log
+}
+
+func (n *fakeNode) mine(head uint64, logs ...types.Log) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.head = head
+	n.logs = append(n.logs, logs...)
+}
+
+func (n *fakeNode) header(context.Context, *big.Int) (*types.Header, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return &types.Header{Number: new(big.Int).SetUint64(n.head)}, nil
+}
+
+func (n *fakeNode) filterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	n.mu.Lock()
+	logs := append([]types.Log(nil), n.logs...)
+	n.mu.Unlock()
+
+	return filterLogs(logs)(ctx, q)
+}
+
+// A test function that tests the `Watch` function.
+func (ts *TableSuite) TestWatch() {
+	ts.Require().NoError(ts.Registry.Put(&registry.Record{ChainID: 18, Name: contract.GoldcoinName, Address: spenderAddr}))
+
+	stop := errors.New("stop")
+
+	ts.Run("Polls http only node without duplicates", func() {
+		clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+		node := &fakeNode{head: 100}
+		clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(18), nil)
+		clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(node.header).AnyTimes()
+		clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(node.filterLogs).AnyTimes()
+
+		// logs before the start block are not streamed
+		node.mine(101,
+			tokenLog(transferTopic, 100, 0, testAddr, spenderAddr, 100),
+			tokenLog(transferTopic, 101, 0, testAddr, spenderAddr, 20),
+		)
+
+		from := uint64(101)
+		var values []int64
+		err := c.Watch(contract.WatchQuery{
+			Events:        []string{contract.EventTransfer},
+			FromBlock:     &from,
+			MinValue:      big.NewInt(10),
+			PollInterval:  time.Millisecond,
+			RetryInterval: 5 * time.Millisecond,
+		}, func(ev contract.Event) error {
+			values = append(values, ev.Value.Int64())
+
+			switch len(values) {
+			case 1:
+				node.mine(103,
+					tokenLog(transferTopic, 102, 0, testAddr, spenderAddr, 1),
+					tokenLog(transferTopic, 103, 0, testAddr, spenderAddr, 30),
+				)
+			case 2:
+				return stop
+			}
+
+			return nil
+		})
+
+		assert.ErrorIs(ts.T(), err, stop)
+		assert.Equal(ts.T(), []int64{20, 30}, values)
+	})
+
+	ts.Run("Filters on from and to addresses", func() {
+		clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+		node := &fakeNode{head: 101}
+		node.mine(101,
+			tokenLog(transferTopic, 101, 0, holderAddr, spenderAddr, 1),
+			tokenLog(approvalTopic, 101, 1, testAddr, holderAddr, 2),
+			tokenLog(transferTopic, 101, 2, testAddr, spenderAddr, 3),
+		)
+
+		clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(18), nil)
+		clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(node.header).AnyTimes()
+		clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(node.filterLogs).AnyTimes()
+
+		from := uint64(101)
+		var values []int64
+		err := c.Watch(contract.WatchQuery{
+			From:         []common.Address{testAddr},
+			FromBlock:    &from,
+			PollInterval: time.Millisecond,
+		}, func(ev contract.Event) error {
+			values = append(values, ev.Value.Int64())
+			if len(values) == 2 {
+				return stop
+			}
+
+			return nil
+		})
+
+		assert.ErrorIs(ts.T(), err, stop)
+		assert.Equal(ts.T(), []int64{2, 3}, values)
+	})
+
+	ts.Run("Catches up after subscription drops", func() {
+		clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+		node := &fakeNode{head: 100}
+		logA := tokenLog(transferTopic, 101, 0, testAddr, spenderAddr, 1)
+		logB := tokenLog(transferTopic, 102, 0, testAddr, spenderAddr, 2)
+		logC := tokenLog(transferTopic, 103, 0, testAddr, spenderAddr, 3)
+
+		dropped := make(chan struct{})
+		subscription := func(drop <-chan struct{}) event.Subscription {
+			return event.NewSubscription(func(quit <-chan struct{}) error {
+				select {
+				case <-drop:
+					return errors.New("websocket closed")
+				case <-quit:
+					return nil
+				}
+			})
+		}
+
+		clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(18), nil)
+		clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(node.header).AnyTimes()
+		clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(node.filterLogs).AnyTimes()
+		gomock.InOrder(
+			clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+					node.mine(101, logA)
+					ch <- logA
+					return subscription(dropped), nil
+				}),
+			clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+					// the subscription replays an event already caught up on
+					node.mine(103, logC)
+					ch <- logB
+					ch <- logC
+					return subscription(nil), nil
+				}),
+		)
+
+		var values []int64
+		err := c.Watch(contract.WatchQuery{
+			Events:        []string{contract.EventTransfer},
+			PollInterval:  time.Millisecond,
+			RetryInterval: 5 * time.Millisecond,
+		}, func(ev contract.Event) error {
+			values = append(values, ev.Value.Int64())
+
+			switch ev.Value.Int64() {
+			case 1:
+				// block 102 is mined while disconnected
+				node.mine(102, logB)
+				close(dropped)
+			case 3:
+				return stop
+			}
+
+			return nil
+		})
+
+		assert.ErrorIs(ts.T(), err, stop)
+		assert.Equal(ts.T(), []int64{1, 2, 3}, values)
+	})
+
+	ts.Run("Forgets delivered events far behind while subscribed", func() {
+		clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+		node := &fakeNode{head: 100}
+		logA := tokenLog(transferTopic, 101, 0, testAddr, spenderAddr, 1)
+
+		clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(18), nil)
+		clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(node.header).AnyTimes()
+		clientMock.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(node.filterLogs).AnyTimes()
+		clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+				// the subscription never drops, the events of block 101 are forgotten once the cursor is 64 blocks past
+				ch <- logA
+				ch <- tokenLog(transferTopic, 230, 0, testAddr, spenderAddr, 2)
+				ch <- logA
+				ch <- tokenLog(transferTopic, 231, 0, testAddr, spenderAddr, 3)
+				return event.NewSubscription(func(quit <-chan struct{}) error {
+					<-quit
+					return nil
+				}), nil
+			})
+
+		var values []int64
+		err := c.Watch(contract.WatchQuery{Events: []string{contract.EventTransfer}}, func(ev contract.Event) error {
+			values = append(values, ev.Value.Int64())
+			if ev.Value.Int64() == 3 {
+				return stop
+			}
+
+			return nil
+		})
+
+		assert.ErrorIs(ts.T(), err, stop)
+		assert.Equal(ts.T(), []int64{1, 2, 1, 3}, values)
+	})
+
+	ts.Run("Stops when context is done", func() {
+		clientMock := contract.NewMockIBlockchain(gomock.NewController(ts.T()))
+		c := contract.NewContract(clientMock, contract.WithRegistry(ts.Registry))
+
+		node := &fakeNode{head: 100}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		clientMock.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(18), nil)
+		clientMock.EXPECT().SubscribeFilterLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("notifications not supported")).AnyTimes()
+		clientMock.EXPECT().HeaderByNumber(gomock.Any(), nil).DoAndReturn(node.header).AnyTimes()
+
+		err := c.WatchContext(ctx, contract.WatchQuery{PollInterval: time.Millisecond}, func(contract.Event) error { return nil })
+		assert.ErrorIs(ts.T(), err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,381 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// DefaultPath is the directory used for the index when none is configured
+const DefaultPath = ".conploy/index"
+
+// Token event names
+const (
+	EventTransfer = "Transfer"
+	EventApproval = "Approval"
+)
+
+// ErrNotSynced is returned when the token has not been indexed yet
+var ErrNotSynced = errors.New("token not indexed")
+
+// Scope identifies the token an index entry belongs to, one index holds tokens of several chains.
+type Scope struct {
+	ChainID uint64
+	Token   common.Address
+}
+
+// Checkpoint is the last block processed by the indexer and its hash as reported by the node, the hash is compared
+// against the chain to detect reorgs. A zero hash is never compared, it marks the block before the deploy block.
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Event is an indexed Transfer or Approval event. For approvals `From` is the owner and `To` the spender.
+type Event struct {
+	Name        string         `json:"event"`
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxHash      common.Hash    `json:"txHash"`
+	LogIndex    uint           `json:"logIndex"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Value       *big.Int       `json:"value"`
+}
+
+// Block is a processed block whose hash is known, either from one of its events or from a checkpoint.
+type Block struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// Holding is the indexed balance of an address.
+type Holding struct {
+	Address common.Address `json:"address"`
+	Balance *big.Int       `json:"balance"`
+}
+
+// Index is a local, file backed store of token events along with the balances and allowances derived from them.
+// Events, derived state and the checkpoint are always written in a single batch so the index never reflects a
+// partially processed block range.
+type Index struct {
+	db *leveldb.DB
+}
+
+// Open opens (or creates) the index stored under the given directory.
+func Open(path string) (*Index, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Index{db: db}, nil
+}
+
+// Close releases the underlying database, the index must not be used afterwards.
+func (ix *Index) Close() error {
+	return ix.db.Close()
+}
+
+// Checkpoint returns the last block processed for the token, `ErrNotSynced` when none was processed yet.
+func (ix *Index) Checkpoint(s Scope) (*Checkpoint, error) {
+	data, err := ix.db.Get(checkpointKey(s), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrNotSynced, s.Token.Hex(), s.ChainID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	cp := new(Checkpoint)
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// Apply stores the events of the blocks up to the checkpoint, which must follow the current one, and updates the
+// balances and allowances they change.
+func (ix *Index) Apply(s Scope, events []Event, cp Checkpoint) error {
+	batch := new(leveldb.Batch)
+	balances := make(map[common.Address]*big.Int)
+
+	for _, ev := range events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+
+		batch.Put(eventKey(s, ev.BlockNumber, ev.LogIndex), data)
+		batch.Put(blockKey(s, ev.BlockNumber), ev.BlockHash.Bytes())
+
+		switch ev.Name {
+		case EventTransfer:
+			if err := ix.move(s, balances, ev.From, ev.To, ev.Value); err != nil {
+				return err
+			}
+		case EventApproval:
+			batch.Put(approvalKey(s, ev.From, ev.To, ev.BlockNumber, ev.LogIndex), []byte(ev.Value.String()))
+		}
+	}
+
+	if cp.Hash != (common.Hash{}) {
+		batch.Put(blockKey(s, cp.Number), cp.Hash.Bytes())
+	}
+
+	if err := putCheckpoint(batch, s, cp); err != nil {
+		return err
+	}
+
+	putBalances(batch, s, balances)
+
+	return ix.db.Write(batch, nil)
+}
+
+// Blocks returns the processed blocks before the given one whose hash is known, latest first.
+func (ix *Index) Blocks(s Scope, before uint64) ([]Block, error) {
+	iter := ix.db.NewIterator(&util.Range{Start: blockKey(s, 0), Limit: blockKey(s, before)}, nil)
+	defer iter.Release()
+
+	var blocks []Block
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		number, err := strconv.ParseUint(string(iter.Key()[len(blockPrefix(s)):]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, Block{Number: number, Hash: common.BytesToHash(iter.Value())})
+	}
+
+	return blocks, iter.Error()
+}
+
+// Rollback removes the events of the blocks after the checkpoint, reverting the balances and allowances they
+// changed, and resets the checkpoint to it. It returns the number of removed events.
+func (ix *Index) Rollback(s Scope, cp Checkpoint) (int, error) {
+	iter := ix.db.NewIterator(&util.Range{Start: eventKey(s, cp.Number+1, 0), Limit: util.BytesPrefix(eventPrefix(s)).Limit}, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	balances := make(map[common.Address]*big.Int)
+	removed := 0
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		ev := new(Event)
+		if err := json.Unmarshal(iter.Value(), ev); err != nil {
+			return 0, err
+		}
+
+		batch.Delete(append([]byte(nil), iter.Key()...))
+
+		switch ev.Name {
+		case EventTransfer:
+			if err := ix.move(s, balances, ev.To, ev.From, ev.Value); err != nil {
+				return 0, err
+			}
+		case EventApproval:
+			batch.Delete(approvalKey(s, ev.From, ev.To, ev.BlockNumber, ev.LogIndex))
+		}
+
+		removed++
+	}
+
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	blocks := ix.db.NewIterator(&util.Range{Start: blockKey(s, cp.Number+1), Limit: util.BytesPrefix(blockPrefix(s)).Limit}, nil)
+	defer blocks.Release()
+
+	for blocks.Next() {
+		batch.Delete(append([]byte(nil), blocks.Key()...))
+	}
+
+	if err := blocks.Error(); err != nil {
+		return 0, err
+	}
+
+	if err := putCheckpoint(batch, s, cp); err != nil {
+		return 0, err
+	}
+
+	putBalances(batch, s, balances)
+
+	return removed, ix.db.Write(batch, nil)
+}
+
+// Events returns the indexed events of the token in chain order.
+func (ix *Index) Events(s Scope) ([]Event, error) {
+	iter := ix.db.NewIterator(util.BytesPrefix(eventPrefix(s)), nil)
+	defer iter.Release()
+
+	var events []Event
+
+	for iter.Next() {
+		var ev Event
+		if err := json.Unmarshal(iter.Value(), &ev); err != nil {
+			return nil, err
+		}
+
+		events = append(events, ev)
+	}
+
+	return events, iter.Error()
+}
+
+// Balance returns the indexed balance of the account, zero when it never held tokens.
+func (ix *Index) Balance(s Scope, account common.Address) (*big.Int, error) {
+	return ix.amount(balanceKey(s, account))
+}
+
+// Balances returns every address with a non zero indexed balance, ordered by address.
+func (ix *Index) Balances(s Scope) ([]Holding, error) {
+	prefix := balancePrefix(s)
+	iter := ix.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	var holdings []Holding
+
+	for iter.Next() {
+		balance, ok := new(big.Int).SetString(string(iter.Value()), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance %q", iter.Value())
+		}
+
+		holdings = append(holdings, Holding{Address: common.HexToAddress(string(iter.Key()[len(prefix):])), Balance: balance})
+	}
+
+	return holdings, iter.Error()
+}
+
+// Allowance returns the value of the latest indexed approval of the spender by the owner, zero when there is none.
+func (ix *Index) Allowance(s Scope, owner, spender common.Address) (*big.Int, error) {
+	iter := ix.db.NewIterator(util.BytesPrefix(allowancePrefix(s, owner, spender)), nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		return new(big.Int), iter.Error()
+	}
+
+	allowance, ok := new(big.Int).SetString(string(iter.Value()), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid allowance %q", iter.Value())
+	}
+
+	return allowance, nil
+}
+
+// move transfers value between the balances, which are loaded from the database on first use. Mints from and
+// burns to the zero address only change the other side.
+func (ix *Index) move(s Scope, balances map[common.Address]*big.Int, from, to common.Address, value *big.Int) error {
+	for _, side := range []struct {
+		account common.Address
+		delta   *big.Int
+	}{{from, new(big.Int).Neg(value)}, {to, value}} {
+		if side.account == (common.Address{}) {
+			continue
+		}
+
+		balance, ok := balances[side.account]
+		if !ok {
+			var err error
+			if balance, err = ix.Balance(s, side.account); err != nil {
+				return err
+			}
+
+			balances[side.account] = balance
+		}
+
+		balance.Add(balance, side.delta)
+	}
+
+	return nil
+}
+
+func (ix *Index) amount(key []byte) (*big.Int, error) {
+	data, err := ix.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return new(big.Int), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	amount, ok := new(big.Int).SetString(string(data), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", data)
+	}
+
+	return amount, nil
+}
+
+func putCheckpoint(batch *leveldb.Batch, s Scope, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	batch.Put(checkpointKey(s), data)
+
+	return nil
+}
+
+// putBalances writes the changed balances, zero balances are deleted so `Balances` only lists holders.
+func putBalances(batch *leveldb.Batch, s Scope, balances map[common.Address]*big.Int) {
+	for account, balance := range balances {
+		if balance.Sign() == 0 {
+			batch.Delete(balanceKey(s, account))
+		} else {
+			batch.Put(balanceKey(s, account), []byte(balance.String()))
+		}
+	}
+}
+
+// keys are laid out as <kind>/<chainID>/<token>/..., block numbers and log indexes are zero padded to keep
+// leveldb's lexicographic ordering equal to chain order
+func scopePrefix(kind string, s Scope) string {
+	return fmt.Sprintf("%s/%d/%s/", kind, s.ChainID, s.Token.Hex())
+}
+
+func checkpointKey(s Scope) []byte {
+	return []byte(scopePrefix("checkpoint", s))
+}
+
+func eventPrefix(s Scope) []byte {
+	return []byte(scopePrefix("event", s))
+}
+
+func eventKey(s Scope, block uint64, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%010d", eventPrefix(s), block, logIndex))
+}
+
+func blockPrefix(s Scope) []byte {
+	return []byte(scopePrefix("block", s))
+}
+
+func blockKey(s Scope, block uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", blockPrefix(s), block))
+}
+
+func balancePrefix(s Scope) []byte {
+	return []byte(scopePrefix("balance", s))
+}
+
+func balanceKey(s Scope, account common.Address) []byte {
+	return append(balancePrefix(s), strings.ToLower(account.Hex())...)
+}
+
+func allowancePrefix(s Scope, owner, spender common.Address) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s/", scopePrefix("approval", s), owner.Hex(), spender.Hex()))
+}
+
+func approvalKey(s Scope, owner, spender common.Address, block uint64, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%010d", allowancePrefix(s, owner, spender), block, logIndex))
+}
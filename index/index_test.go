@@ -0,0 +1,97 @@
+package index_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/index"
+)
+
+func TestIndex(t *testing.T) {
+	ix, err := index.Open(t.TempDir())
+	require.NoError(t, err)
+	defer ix.Close()
+
+	var (
+		scope   = index.Scope{ChainID: 9000, Token: common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d")}
+		other   = index.Scope{ChainID: 9001, Token: scope.Token}
+		alice   = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+		bob     = common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+		minted  = index.Event{Name: index.EventTransfer, BlockNumber: 10, BlockHash: common.HexToHash("0x0a"), To: alice, Value: big.NewInt(100)}
+		sent    = index.Event{Name: index.EventTransfer, BlockNumber: 12, BlockHash: common.HexToHash("0x0c"), From: alice, To: bob, Value: big.NewInt(40)}
+		approve = index.Event{Name: index.EventApproval, BlockNumber: 12, BlockHash: common.HexToHash("0x0c"), LogIndex: 1, From: alice, To: bob, Value: big.NewInt(25)}
+		raise   = index.Event{Name: index.EventApproval, BlockNumber: 14, BlockHash: common.HexToHash("0x0e"), From: alice, To: bob, Value: big.NewInt(60)}
+	)
+
+	_, err = ix.Checkpoint(scope)
+	assert.ErrorIs(t, err, index.ErrNotSynced)
+
+	require.NoError(t, ix.Apply(scope, []index.Event{minted}, index.Checkpoint{Number: 11, Hash: common.HexToHash("0x0b")}))
+	require.NoError(t, ix.Apply(scope, []index.Event{sent, approve, raise}, index.Checkpoint{Number: 15, Hash: common.HexToHash("0x0f")}))
+
+	balanceOf := func(s index.Scope, account common.Address) int64 {
+		bal, err := ix.Balance(s, account)
+		require.NoError(t, err)
+
+		return bal.Int64()
+	}
+
+	allowance := func() int64 {
+		a, err := ix.Allowance(scope, alice, bob)
+		require.NoError(t, err)
+
+		return a.Int64()
+	}
+
+	t.Run("Derives balances and allowances", func(t *testing.T) {
+		assert.Equal(t, int64(60), balanceOf(scope, alice))
+		assert.Equal(t, int64(40), balanceOf(scope, bob))
+		assert.Equal(t, int64(0), balanceOf(other, alice))
+		assert.Equal(t, int64(60), allowance())
+
+		holders, err := ix.Balances(scope)
+		require.NoError(t, err)
+		assert.Equal(t, []index.Holding{{Address: bob, Balance: big.NewInt(40)}, {Address: alice, Balance: big.NewInt(60)}}, holders)
+	})
+
+	t.Run("Lists known blocks latest first", func(t *testing.T) {
+		blocks, err := ix.Blocks(scope, 15)
+		require.NoError(t, err)
+		assert.Equal(t, []index.Block{
+			{Number: 14, Hash: raise.BlockHash},
+			{Number: 12, Hash: sent.BlockHash},
+			{Number: 11, Hash: common.HexToHash("0x0b")},
+			{Number: 10, Hash: minted.BlockHash},
+		}, blocks)
+	})
+
+	t.Run("Rollback reverts events after checkpoint", func(t *testing.T) {
+		removed, err := ix.Rollback(scope, index.Checkpoint{Number: 12, Hash: sent.BlockHash})
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, int64(25), allowance())
+
+		removed, err = ix.Rollback(scope, index.Checkpoint{Number: 11, Hash: common.HexToHash("0x0b")})
+		require.NoError(t, err)
+		assert.Equal(t, 2, removed)
+		assert.Equal(t, int64(100), balanceOf(scope, alice))
+		assert.Equal(t, int64(0), balanceOf(scope, bob))
+		assert.Equal(t, int64(0), allowance())
+
+		holders, err := ix.Balances(scope)
+		require.NoError(t, err)
+		assert.Len(t, holders, 1)
+
+		cp, err := ix.Checkpoint(scope)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11), cp.Number)
+
+		events, err := ix.Events(scope)
+		require.NoError(t, err)
+		assert.Equal(t, []index.Event{minted}, events)
+	})
+}
@@ -1,110 +1,221 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 
+	"github.com/gopherine/evmos-conploy/config"
 	"github.com/gopherine/evmos-conploy/contract"
+	"github.com/gopherine/evmos-conploy/index"
+	"github.com/gopherine/evmos-conploy/registry"
+	"github.com/gopherine/evmos-conploy/signer"
 )
 
 func main() {
-	// Initialize godot  env to load env config
-	err := godotenv.Load()
+	// Settings are layered: flags override the environment, which overrides .env, which overrides the network
+	// profiles. Every layer is optional, .env does not replace variables already set in the environment.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warn().Err(err).Msg("unable to load .env file")
+	}
+
+	// the contract module is configured for the network selected on the command line once it is parsed
+	c := &contract.Contract{}
+	// Creating a CLI app with a subcommand per action, refer makefile on how to trigger them
+	app := newApp(c, connect, offlineSigner)
+
+	// Interrupting the cli cancels the context of the running command, which aborts pending node calls
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Running the app with the arguments passed in the command line, the command has released what it opened by
+	// the time its error is returned and the cli exits with the code matching it.
+	if err := app.RunContext(ctx, os.Args); err != nil {
+		log.Error().Err(err).Msg("command failed")
+		stop()
+		os.Exit(exitStatus(err))
+	}
+}
+
+// connect configures the contract module for the network selected with `--network`: it dials the node of the
+// profile, or the one given with `--rpc`, opens the registry under the profile's namespace along with the index,
+// and loads the signer, a keystore is only decrypted once a transaction is signed. It is only called by the commands
+// talking to the node. The returned function closes what was opened.
+func connect(cCtx *cli.Context, c *contract.Contract) (func(), error) {
+	network, err := selectNetwork(cCtx)
 	if err != nil {
-		log.Fatal().Msg("Error loading .env file")
+		return nil, err
+	}
+
+	if rpc := cCtx.String("rpc"); rpc != "" {
+		network.RPC = []string{rpc}
 	}
 
 	// Connect to client
-	client, err := ethclient.Dial(os.Getenv("CLIENT_URL"))
+	client, err := network.Dial(cCtx.Context)
+	if errors.Is(err, config.ErrNoRPC) {
+		return nil, cli.Exit(err, exitUsage)
+	} else if err != nil {
+		return nil, cli.Exit(fmt.Errorf("client connection failed: %w", err), exitFailure)
+	}
+
+	log.Info().Msg("Client connection successful")
+
+	// Open the local registry where deployments are recorded, every network namespace has its own directory
+	reg, err := registry.Open(filepath.Join(cCtx.String("registry"), network.Registry))
 	if err != nil {
-		log.Fatal().Err(err).Msg("Client connection failed")
-	} else {
-		log.Info().Msg("Client connection successful")
+		client.Close()
+		return nil, cli.Exit(fmt.Errorf("unable to open deployment registry: %w", err), exitFailure)
+	}
+
+	// Open the local index token events are synced to, it is only written by `index sync`
+	ix, err := index.Open(cCtx.String("index"))
+	if err != nil {
+		reg.Close()
+		client.Close()
+		return nil, cli.Exit(fmt.Errorf("unable to open event index: %w", err), exitFailure)
+	}
+
+	closeAll := func() {
+		ix.Close()
+		reg.Close()
+		client.Close()
+	}
+
+	opts := []contract.Option{
+		contract.WithRegistry(reg),
+		contract.WithIndex(ix),
+		contract.WithGasPolicy(gasPolicy(network.Gas)),
+		contract.WithChainID(network.ChainID),
+	}
+
+	// Commands sending transactions fail with `contract.ErrNoSigner` when no signer is configured
+	s, err := loadSigner(network.Signer)
+	if err != nil {
+		closeAll()
+		return nil, cli.Exit(fmt.Errorf("unable to load signer: %w", err), exitFailure)
+	} else if s != nil {
+		opts = append(opts, contract.WithSigner(s))
 	}
 
 	// initialize deploy contract module
-	c := contract.NewContract(client)
-	// goldcoin contract instance
-	instance := c.Load()
-	// Creating a CLI app with flags and actions.
-	// refer makefile on how to manually trigger these flags
-	app := &cli.App{
-		Flags: []cli.Flag{
-			&cli.BoolFlag{
-				Name:    "deploy",
-				Aliases: []string{"d"},
-				Usage:   "Deploy smart contract",
-			},
-			&cli.BoolFlag{
-				Name:    "reciept",
-				Aliases: []string{"r"},
-				Usage:   "Check if smart contract is deployed",
-			},
-			&cli.BoolFlag{
-				Name:    "transact",
-				Aliases: []string{"t"},
-				Usage:   "Transfer tokens from one address to other",
-			},
-			&cli.BoolFlag{
-				Name:    "balanceOf",
-				Aliases: []string{"b"},
-				Usage:   "Check balance of given address",
-			},
-		},
-		Action: func(cCtx *cli.Context) error {
-			if cCtx.Bool("deploy") {
-				_, addrHash, txHash, err := c.Deploy()
-				if err != nil {
-					log.Fatal().Err(err).Msg("Unable to deploy")
-				}
-				log.Info().Msgf("Address: %s", addrHash)
-				log.Info().Msgf("TXHash: ", txHash)
-			} else if cCtx.Bool("reciept") {
-				reciept, err := c.Reciept()
-				if err != nil {
-					log.Fatal().Err(err).Msg("Unable to get reciept")
-				}
-				log.Info().Msgf("Reciept: %v", reciept)
-			} else if cCtx.Bool("transact") {
-				if cCtx.NArg() > 1 {
-					tx, err := c.TransferTokens(instance, cCtx.Args().Get(1) /*address*/, cCtx.Args().Get(0) /*amount*/)
-					if err != nil {
-						log.Fatal().Err(err).Msg("Transaction failed")
-					}
-
-					log.Info().Msgf("TXHash: %v", tx.Hash().String())
-				} else {
-					log.Fatal().Err(errors.New("Need more arguments, in the format make run fromAddress toAddress")).Msg("transaction failed")
-				}
-			} else if cCtx.Bool("balanceOf") {
-				if cCtx.NArg() > 0 {
-					bal, err := c.CheckBal(instance, cCtx.Args().Get(0))
-					if err != nil {
-						log.Fatal().Err(err).Msg("Unable to get balance")
-					}
-
-					log.Info().Msgf("Balance: %v", bal)
-				} else {
-					bal, err := c.CheckBal(instance, "")
-					if err != nil {
-						log.Fatal().Err(err).Msg("Unable to get balance")
-					}
-
-					log.Info().Msgf("Balance: %v", bal)
-				}
-			}
-
-			return nil
-		},
-	}
-
-	// Running the app with the arguments passed in the command line.
-	if err := app.Run(os.Args); err != nil {
-		log.Fatal().Err(err)
+	*c = *contract.NewContract(client, opts...)
+
+	return closeAll, nil
+}
+
+// offlineSigner loads the signer of the network selected with `--network` without connecting to its node, for the
+// commands signing on a machine without network access.
+func offlineSigner(cCtx *cli.Context) (contract.Signer, error) {
+	network, err := selectNetwork(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := loadSigner(network.Signer)
+	if err != nil {
+		return nil, cli.Exit(fmt.Errorf("unable to load signer: %w", err), exitFailure)
+	} else if s == nil {
+		return nil, cli.Exit(contract.ErrNoSigner, exitUsage)
+	}
+
+	return s, nil
+}
+
+// selectNetwork returns the profile selected with `--network` from the config file given with `--config`.
+func selectNetwork(cCtx *cli.Context) (*config.Network, error) {
+	cfg, err := config.Load(cCtx.String("config"), cCtx.IsSet("config"))
+	if err != nil {
+		return nil, cli.Exit(fmt.Errorf("unable to load config: %w", err), exitUsage)
+	}
+
+	network, err := cfg.Select(cCtx.String("network"))
+	if err != nil {
+		return nil, cli.Exit(err, exitUsage)
+	}
+
+	return network, nil
+}
+
+// gasPolicy returns the gas policy set in .env with the non zero settings of the network profile applied over
+// it. Dynamic fee transactions are used whenever the chain supports them, LEGACY_TX forces legacy ones. Unset or
+// invalid gas multiplier and cap fall back to the contract module defaults.
+func gasPolicy(gas config.Gas) contract.GasPolicy {
+	legacy, _ := strconv.ParseBool(os.Getenv("LEGACY_TX"))
+	multiplier, _ := strconv.ParseFloat(os.Getenv("GAS_MULTIPLIER"), 64)
+	gasCap, _ := strconv.ParseUint(os.Getenv("GAS_CAP"), 10, 64)
+
+	if gas.Multiplier != 0 {
+		multiplier = gas.Multiplier
+	}
+
+	if gas.Cap != 0 {
+		gasCap = gas.Cap
 	}
+
+	return contract.GasPolicy{Legacy: legacy || gas.Legacy, Multiplier: multiplier, Cap: gasCap}
+}
+
+// loadSigner returns the signer referenced by the network profile, or the one configured in .env when the profile
+// has none. An encrypted keystore takes precedence over a mnemonic which takes precedence over a raw private key.
+// A nil signer is returned when none of them is configured.
+func loadSigner(ref config.Signer) (contract.Signer, error) {
+	if ref.IsZero() {
+		ref = config.Signer{
+			Keystore:       os.Getenv("KEYSTORE_PATH"),
+			PasswordFile:   os.Getenv("KEYSTORE_PASSWORD_FILE"),
+			MnemonicFile:   os.Getenv("MNEMONIC_FILE"),
+			MnemonicEnv:    "MNEMONIC",
+			PassphraseEnv:  "MNEMONIC_PASSPHRASE",
+			DerivationPath: os.Getenv("DERIVATION_PATH"),
+			PrivateKeyEnv:  "OWNER_PRIVATEKEY",
+		}
+	}
+
+	// the keystore is only decrypted, and its passphrase read, once a transaction is signed
+	if ref.Keystore != "" {
+		return signer.OpenKeystore(ref.Keystore, func() (string, error) {
+			return signer.ReadPassphrase(ref.PasswordFile, "Keystore passphrase: ")
+		})
+	}
+
+	mnemonic := getenv(ref.MnemonicEnv)
+	if ref.MnemonicFile != "" {
+		data, err := os.ReadFile(ref.MnemonicFile)
+		if err != nil {
+			return nil, err
+		}
+
+		mnemonic = string(data)
+	}
+
+	// an empty derivation path derives the first account, `signer.DefaultDerivationPath`
+	if mnemonic != "" {
+		return signer.FromMnemonic(mnemonic, getenv(ref.PassphraseEnv), ref.DerivationPath)
+	}
+
+	if key := getenv(ref.PrivateKeyEnv); key != "" {
+		return signer.FromHex(key)
+	}
+
+	return nil, nil
+}
+
+// getenv returns the value of the named environment variable, empty when no name is given.
+func getenv(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return os.Getenv(name)
 }
@@ -0,0 +1,369 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalid is returned for manifests that can not be applied, eg. with duplicate step ids or dependency cycles
+var ErrInvalid = errors.New("invalid manifest")
+
+// reference matches `${Name}`, it is replaced by the address of the contract deployed under Name
+var reference = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// Manifest lists contracts deployed together and the calls made once they are deployed, eg.
+//
+//	name: tokens
+//	contracts:
+//	  - name: Goldcoin
+//	  - name: Vault
+//	    abi: abi/Vault.abi
+//	    bin: bin/Vault.bin
+//	    args: [vault, "${Goldcoin}", 1000000]
+//	calls:
+//	  - contract: Goldcoin
+//	    method: approve
+//	    args: ["${Vault}", 1000]
+//
+// Steps are applied in dependency order: a step runs after the contracts it references and the steps listed in its
+// `after`, calls also run in the order they are declared.
+type Manifest struct {
+	// Name scopes the progress of the steps in the registry, defaults to the file name without extension
+	Name      string    `yaml:"name"`
+	Contracts []*Deploy `yaml:"contracts"`
+	Calls     []*Call   `yaml:"calls"`
+	// Dir is the directory the artifact paths are relative to, the directory of the manifest file
+	Dir string `yaml:"-"`
+}
+
+// Deploy is a contract deployment, the step id is the name the deployment is recorded under.
+type Deploy struct {
+	Name string `yaml:"name"`
+	// ABI and Bin are the compiled contract, relative to the manifest file, the bundled goldcoin contract is
+	// deployed when both are empty
+	ABI  string `yaml:"abi"`
+	Bin  string `yaml:"bin"`
+	Args Args   `yaml:"args"`
+	// Salt deploys the contract with CREATE2 through the deterministic deployer when set
+	Salt  string   `yaml:"salt"`
+	After []string `yaml:"after"`
+}
+
+// Call is a transaction calling a method of a contract, the contract is a name or an address.
+type Call struct {
+	// ID defaults to <contract>.<method>, it must be set when the same method is called twice
+	ID       string `yaml:"id"`
+	Contract string `yaml:"contract"`
+	// ABI is needed for contracts that are not recorded in the registry, relative to the manifest file
+	ABI    string `yaml:"abi"`
+	Method string `yaml:"method"`
+	Args   Args   `yaml:"args"`
+	// Value is the wei sent along with a call to a payable method
+	Value string   `yaml:"value"`
+	After []string `yaml:"after"`
+}
+
+// Args are the arguments of a constructor or method in the form `contract.ParseArguments` takes them. Scalars are
+// taken as they are written and lists and maps, for arrays and tuples, are converted to JSON.
+type Args []string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (a *Args) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: args must be a list", node.Line)
+	}
+
+	args := make(Args, 0, len(node.Content))
+	for _, n := range node.Content {
+		if n.Kind == yaml.ScalarNode {
+			args = append(args, n.Value)
+			continue
+		}
+
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+
+		args = append(args, string(data))
+	}
+
+	*a = args
+
+	return nil
+}
+
+// Step is a deployment or a call of a manifest, exactly one of `Deploy` and `Call` is set.
+type Step struct {
+	ID     string
+	Deploy *Deploy
+	Call   *Call
+	// DependsOn are the ids of the steps that must be applied first
+	DependsOn []string
+}
+
+// Load reads a YAML or JSON manifest, its artifact paths are relative to the manifest file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if m.Name == "" {
+		m.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	m.Dir = filepath.Dir(path)
+
+	return m, nil
+}
+
+// Parse decodes a YAML or JSON manifest and checks it can be planned, unknown fields are rejected.
+func Parse(data []byte) (*Manifest, error) {
+	m := new(Manifest)
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	if _, err := m.Plan(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Path resolves an artifact path of the manifest, empty paths are kept empty.
+func (m *Manifest) Path(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(m.Dir, path)
+}
+
+// Plan returns the steps of the manifest in the order they are applied. Among the steps whose dependencies are
+// applied the one declared first goes first, contracts are declared before calls.
+func (m *Manifest) Plan() ([]*Step, error) {
+	steps, err := m.steps()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(steps))
+	plan := make([]*Step, 0, len(steps))
+
+	for len(plan) < len(steps) {
+		var next *Step
+
+		for _, s := range steps {
+			if !applied[s.ID] && ready(s, applied) {
+				next = s
+				break
+			}
+		}
+
+		if next == nil {
+			var pending []string
+			for _, s := range steps {
+				if !applied[s.ID] {
+					pending = append(pending, s.ID)
+				}
+			}
+
+			return nil, fmt.Errorf("%w: dependency cycle between %s", ErrInvalid, strings.Join(pending, ", "))
+		}
+
+		applied[next.ID] = true
+		plan = append(plan, next)
+	}
+
+	return plan, nil
+}
+
+func ready(s *Step, applied map[string]bool) bool {
+	for _, id := range s.DependsOn {
+		if !applied[id] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// steps validates the manifest and returns its steps in declaration order along with their dependencies.
+func (m *Manifest) steps() ([]*Step, error) {
+	var steps []*Step
+
+	ids := map[string]bool{}
+	add := func(s *Step) error {
+		if s.ID == "" {
+			return fmt.Errorf("%w: step without name", ErrInvalid)
+		}
+
+		if ids[s.ID] {
+			return fmt.Errorf("%w: duplicate step %s, set an id on calls of the same method", ErrInvalid, s.ID)
+		}
+
+		ids[s.ID] = true
+		steps = append(steps, s)
+
+		return nil
+	}
+
+	deployed := map[string]bool{}
+
+	for _, d := range m.Contracts {
+		if (d.ABI == "") != (d.Bin == "") {
+			return nil, fmt.Errorf("%w: contract %s needs both abi and bin", ErrInvalid, d.Name)
+		}
+
+		if err := add(&Step{ID: d.Name, Deploy: d}); err != nil {
+			return nil, err
+		}
+
+		deployed[d.Name] = true
+	}
+
+	previous := ""
+
+	for _, c := range m.Calls {
+		if c.Contract == "" || c.Method == "" {
+			return nil, fmt.Errorf("%w: call %s needs a contract and a method", ErrInvalid, c.ID)
+		}
+
+		id := c.ID
+		if id == "" {
+			id = c.Contract + "." + c.Method
+		}
+
+		s := &Step{ID: id, Call: c}
+		if deployed[c.Contract] {
+			s.DependsOn = append(s.DependsOn, c.Contract)
+		}
+
+		if previous != "" {
+			s.DependsOn = append(s.DependsOn, previous)
+		}
+
+		if err := add(s); err != nil {
+			return nil, err
+		}
+
+		previous = id
+	}
+
+	for _, s := range steps {
+		args, after := s.args()
+
+		// references to contracts outside the manifest are resolved from the registry when applied
+		for _, name := range References(args...) {
+			if deployed[name] {
+				s.DependsOn = append(s.DependsOn, name)
+			}
+		}
+
+		for _, id := range after {
+			if !ids[id] {
+				return nil, fmt.Errorf("%w: step %s runs after unknown step %s", ErrInvalid, s.ID, id)
+			}
+
+			s.DependsOn = append(s.DependsOn, id)
+		}
+
+		for _, id := range s.DependsOn {
+			if id == s.ID {
+				return nil, fmt.Errorf("%w: step %s depends on itself", ErrInvalid, s.ID)
+			}
+		}
+	}
+
+	return steps, nil
+}
+
+// args returns the arguments of the step that may hold references and the steps it is declared to run after.
+func (s *Step) args() ([]string, []string) {
+	if s.Deploy != nil {
+		return append([]string{s.Deploy.Salt}, s.Deploy.Args...), s.Deploy.After
+	}
+
+	return append([]string{s.Call.Value}, s.Call.Args...), s.Call.After
+}
+
+// Digest hashes the definition of the step along with the bytecode it deploys, it changes whenever the step is
+// edited or its contract recompiled.
+func (m *Manifest) Digest(s *Step) (common.Hash, error) {
+	def, err := json.Marshal(struct {
+		Deploy *Deploy
+		Call   *Call
+	}{s.Deploy, s.Call})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if s.Deploy == nil || s.Deploy.Bin == "" {
+		return crypto.Keccak256Hash(def), nil
+	}
+
+	bin, err := os.ReadFile(m.Path(s.Deploy.Bin))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(def, bytes.TrimSpace(bin)), nil
+}
+
+// References returns the contract names referenced with `${Name}` in the given strings.
+func References(args ...string) []string {
+	var names []string
+	for _, arg := range args {
+		for _, match := range reference.FindAllStringSubmatch(arg, -1) {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// Expand replaces every `${Name}` in s with the 0x hex address lookup returns for Name.
+func Expand(s string, lookup func(name string) (common.Address, error)) (string, error) {
+	var err error
+
+	expanded := reference.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		var addr common.Address
+		if addr, err = lookup(reference.FindStringSubmatch(match)[1]); err != nil {
+			return match
+		}
+
+		return addr.Hex()
+	})
+
+	return expanded, err
+}
@@ -0,0 +1,139 @@
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/manifest"
+)
+
+const tokens = `
+name: tokens
+contracts:
+  - name: Vault
+    abi: abi/Vault.abi
+    bin: bin/Vault.bin
+    args: [vault, "${Goldcoin}", 1000000, [1, 2], {label: gold, owner: "${Goldcoin}"}]
+  - name: Goldcoin
+calls:
+  - contract: Goldcoin
+    method: approve
+    args: ["${Vault}", 1000]
+  - id: deposit
+    contract: Vault
+    method: deposit
+    value: "10"
+    args: [1000]
+  - contract: ${Vault}
+    method: pause
+    after: [Vault]
+`
+
+func ids(steps []*manifest.Step) []string {
+	var out []string
+	for _, s := range steps {
+		out = append(out, s.ID)
+	}
+
+	return out
+}
+
+func TestPlan(t *testing.T) {
+	m, err := manifest.Parse([]byte(tokens))
+	require.NoError(t, err)
+
+	plan, err := m.Plan()
+	require.NoError(t, err)
+
+	// Vault references Goldcoin so it is deployed second although declared first
+	assert.Equal(t, []string{"Goldcoin", "Vault", "Goldcoin.approve", "deposit", "${Vault}.pause"}, ids(plan))
+
+	vault := plan[1].Deploy
+	assert.Equal(t, manifest.Args{"vault", "${Goldcoin}", "1000000", "[1,2]", `{"label":"gold","owner":"${Goldcoin}"}`}, vault.Args)
+	assert.Equal(t, []string{"Vault", "Goldcoin.approve"}, plan[3].DependsOn)
+
+	invalid := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "Dependency cycle", manifest: `
+contracts:
+  - {name: A, abi: a.abi, bin: a.bin, args: ["${B}"]}
+  - {name: B, abi: b.abi, bin: b.bin, args: ["${A}"]}`},
+		{name: "Self reference", manifest: `
+contracts:
+  - {name: A, abi: a.abi, bin: a.bin, args: ["${A}"]}`},
+		{name: "Duplicate call", manifest: `
+calls:
+  - {contract: A, method: pause}
+  - {contract: A, method: pause}`},
+		{name: "Unknown after", manifest: `
+contracts:
+  - {name: A, after: [B]}`},
+		{name: "Missing bin", manifest: `
+contracts:
+  - {name: A, abi: a.abi}`},
+		{name: "Unknown field", manifest: `
+contracts:
+  - {name: A, constructor: [1]}`},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manifest.Parse([]byte(tt.manifest))
+			assert.ErrorIs(t, err, manifest.ErrInvalid)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "Vault.bin"), []byte("6080\n"), 0o600))
+
+	path := filepath.Join(dir, "release.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"contracts": [{"name": "Vault", "abi": "abi/Vault.abi", "bin": "bin/Vault.bin"}]}`), 0o600))
+
+	m, err := manifest.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "release", m.Name, "named after the file")
+	assert.Equal(t, filepath.Join(dir, "bin", "Vault.bin"), m.Path(m.Contracts[0].Bin))
+
+	plan, err := m.Plan()
+	require.NoError(t, err)
+
+	digest, err := m.Digest(plan[0])
+	require.NoError(t, err)
+
+	// recompiling the contract changes the digest of its step
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "Vault.bin"), []byte("6081\n"), 0o600))
+
+	changed, err := m.Digest(plan[0])
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, changed)
+}
+
+func TestExpand(t *testing.T) {
+	vault := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	lookup := func(name string) (common.Address, error) {
+		if name != "Vault" {
+			return common.Address{}, os.ErrNotExist
+		}
+
+		return vault, nil
+	}
+
+	expanded, err := manifest.Expand(`["${Vault}","${Vault}"]`, lookup)
+	require.NoError(t, err)
+	assert.Equal(t, `["`+vault.Hex()+`","`+vault.Hex()+`"]`, expanded)
+
+	_, err = manifest.Expand("${Missing}", lookup)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.Equal(t, []string{"Vault", "Missing"}, manifest.References("${Vault}", "1", "${Missing}"))
+}
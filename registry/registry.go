@@ -0,0 +1,184 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// DefaultPath is the directory used for the registry when none is configured
+const DefaultPath = ".conploy/registry"
+
+// ErrNotFound is returned when no deployment matches the given lookup
+var ErrNotFound = errors.New("deployment not found in registry")
+
+// Record describes a single contract deployment made by conploy.
+type Record struct {
+	ChainID      uint64         `json:"chainId"`
+	Name         string         `json:"name"`
+	Address      common.Address `json:"address"`
+	TxHash       common.Hash    `json:"txHash"`
+	Deployer     common.Address `json:"deployer"`
+	BlockNumber  uint64         `json:"blockNumber"`
+	BytecodeHash common.Hash    `json:"bytecodeHash"`
+	Timestamp    time.Time      `json:"timestamp"`
+	// ABI is the JSON ABI of the contract, records made before it was kept have none
+	ABI json.RawMessage `json:"abi,omitempty"`
+	// Salt is set for CREATE2 deployments, the address then only depends on it and the init code
+	Salt *common.Hash `json:"salt,omitempty"`
+}
+
+// Registry is a local, file backed store of deployment records, keyed by chain id and contract name
+// so the same tool can be pointed at several networks without the records clashing.
+type Registry struct {
+	db *leveldb.DB
+}
+
+// Open opens (or creates) the registry stored under the given directory.
+func Open(path string) (*Registry, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registry{db: db}, nil
+}
+
+// Close releases the underlying database, the registry must not be used afterwards.
+func (r *Registry) Close() error {
+	return r.db.Close()
+}
+
+// Put stores the record, a record with the same chain id, name and timestamp is overwritten
+// which is how a record gets updated once more details (eg. block number) are known.
+func (r *Registry) Put(rec *Record) error {
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Put(recordKey(rec), data, nil)
+}
+
+// Latest returns the most recent deployment of the named contract on the given chain.
+func (r *Registry) Latest(chainID uint64, name string) (*Record, error) {
+	iter := r.db.NewIterator(util.BytesPrefix(namePrefix(chainID, name)), nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrNotFound, name, chainID)
+	}
+
+	return decode(iter.Value())
+}
+
+// List returns every deployment recorded for the given chain, ordered by contract name and
+// then by deployment time.
+func (r *Registry) List(chainID uint64) ([]*Record, error) {
+	iter := r.db.NewIterator(util.BytesPrefix(chainPrefix(chainID)), nil)
+	defer iter.Release()
+
+	var records []*Record
+
+	for iter.Next() {
+		rec, err := decode(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, iter.Error()
+}
+
+// Step is the progress of a manifest step applied by `conploy apply`, it is saved with the step's signed transaction
+// before it is sent and again once it is mined so an interrupted apply resumes without sending it twice.
+type Step struct {
+	ChainID  uint64 `json:"chainId"`
+	Manifest string `json:"manifest"`
+	ID       string `json:"id"`
+	// Digest identifies the definition of the step, a step edited after it was applied has another digest
+	Digest common.Hash `json:"digest"`
+	TxHash common.Hash `json:"txHash"`
+	// Raw is the signed transaction, kept until it is mined to send it again as is when the node lost it
+	Raw hexutil.Bytes `json:"raw,omitempty"`
+	// Address is the contract deployed by a deploy step
+	Address common.Address `json:"address,omitempty"`
+	// Done is set once the transaction of the step is mined successfully
+	Done      bool      `json:"done"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PutStep stores the step, replacing the previous progress of the same step.
+func (r *Registry) PutStep(step *Step) error {
+	step.Timestamp = time.Now().UTC()
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Put(stepKey(step.ChainID, step.Manifest, step.ID), data, nil)
+}
+
+// Step returns the progress of a manifest step on the given chain, `ErrNotFound` when it was never applied.
+func (r *Registry) Step(chainID uint64, manifest, id string) (*Step, error) {
+	data, err := r.db.Get(stepKey(chainID, manifest, id), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, fmt.Errorf("%w: step %s of %s on chain %d", ErrNotFound, id, manifest, chainID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	step := new(Step)
+	if err := json.Unmarshal(data, step); err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+func decode(data []byte) (*Record, error) {
+	rec := new(Record)
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// keys are laid out as deploy/<chainID>/<name>/<unix nano>, the zero padded timestamp keeps
+// leveldb's lexicographic ordering equal to deployment order
+func chainPrefix(chainID uint64) []byte {
+	return []byte(fmt.Sprintf("deploy/%d/", chainID))
+}
+
+func namePrefix(chainID uint64, name string) []byte {
+	return []byte(fmt.Sprintf("deploy/%d/%s/", chainID, name))
+}
+
+func recordKey(rec *Record) []byte {
+	return []byte(fmt.Sprintf("deploy/%d/%s/%020d", rec.ChainID, rec.Name, rec.Timestamp.UnixNano()))
+}
+
+// manifest steps are kept apart from deployments under step/<chainID>/<manifest>/<step id>
+func stepKey(chainID uint64, manifest, id string) []byte {
+	return []byte(fmt.Sprintf("step/%d/%s/%s", chainID, manifest, id))
+}
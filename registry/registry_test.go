@@ -0,0 +1,138 @@
+package registry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	reg, err := registry.Open(t.TempDir())
+	require.NoError(t, err)
+	defer reg.Close()
+
+	first := &registry.Record{
+		ChainID:   9000,
+		Name:      "Goldcoin",
+		Address:   common.HexToAddress("0x4592d8f8d7b001e72cb26a73e4fa1806a51ac79d"),
+		TxHash:    common.HexToHash("0x01"),
+		Timestamp: time.Unix(100, 0),
+	}
+	second := &registry.Record{
+		ChainID:   9000,
+		Name:      "Goldcoin",
+		Address:   common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf"),
+		TxHash:    common.HexToHash("0x02"),
+		Timestamp: time.Unix(200, 0),
+	}
+	otherChain := &registry.Record{
+		ChainID:   9001,
+		Name:      "Goldcoin",
+		TxHash:    common.HexToHash("0x03"),
+		Timestamp: time.Unix(300, 0),
+	}
+
+	for _, rec := range []*registry.Record{second, first, otherChain} {
+		require.NoError(t, reg.Put(rec))
+	}
+
+	subtests := []struct {
+		name     string
+		chainID  uint64
+		contract string
+		want     common.Hash
+		wantErr  error
+	}{
+		{
+			name:     "Latest returns most recent deployment",
+			chainID:  9000,
+			contract: "Goldcoin",
+			want:     second.TxHash,
+		},
+		{
+			name:     "Latest is scoped by chain id",
+			chainID:  9001,
+			contract: "Goldcoin",
+			want:     otherChain.TxHash,
+		},
+		{
+			name:     "Unknown contract",
+			chainID:  9000,
+			contract: "Silvercoin",
+			wantErr:  registry.ErrNotFound,
+		},
+		{
+			name:     "Unknown chain",
+			chainID:  1,
+			contract: "Goldcoin",
+			wantErr:  registry.ErrNotFound,
+		},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := reg.Latest(tt.chainID, tt.contract)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rec.TxHash)
+		})
+	}
+
+	t.Run("List returns records in deployment order", func(t *testing.T) {
+		records, err := reg.List(9000)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, first.TxHash, records[0].TxHash)
+		assert.Equal(t, second.TxHash, records[1].TxHash)
+	})
+
+	t.Run("Put overwrites record with the same timestamp", func(t *testing.T) {
+		second.BlockNumber = 42
+		require.NoError(t, reg.Put(second))
+
+		rec, err := reg.Latest(9000, "Goldcoin")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), rec.BlockNumber)
+
+		records, err := reg.List(9000)
+		require.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+}
+
+func TestSteps(t *testing.T) {
+	reg, err := registry.Open(t.TempDir())
+	require.NoError(t, err)
+	defer reg.Close()
+
+	_, err = reg.Step(9000, "tokens", "Goldcoin")
+	assert.ErrorIs(t, err, registry.ErrNotFound)
+
+	step := &registry.Step{ChainID: 9000, Manifest: "tokens", ID: "Goldcoin", TxHash: common.HexToHash("0x01")}
+	require.NoError(t, reg.PutStep(step))
+
+	step.Done = true
+	require.NoError(t, reg.PutStep(step))
+
+	got, err := reg.Step(9000, "tokens", "Goldcoin")
+	require.NoError(t, err)
+	assert.True(t, got.Done)
+	assert.Equal(t, step.TxHash, got.TxHash)
+
+	_, err = reg.Step(9001, "tokens", "Goldcoin")
+	assert.ErrorIs(t, err, registry.ErrNotFound, "steps are scoped by chain id")
+
+	records, err := reg.List(9000)
+	require.NoError(t, err)
+	assert.Empty(t, records, "steps are not listed as deployments")
+}
@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/term"
+)
+
+// ErrNoTerminal is returned when a passphrase has to be prompted for but stdin is not a terminal
+var ErrNoTerminal = errors.New("passphrase prompt needs a terminal, provide a passphrase file instead")
+
+// FromKeystore returns a signer for a go-ethereum encrypted keystore (v3) JSON file, decrypted with the passphrase.
+func FromKeystore(path, passphrase string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt keystore %s: %w", path, err)
+	}
+
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// KeystoreSigner signs with the key of an encrypted keystore (v3) JSON file, which is only decrypted when the first
+// transaction is signed. Its address is read from the file, so using the account without signing neither runs the
+// key derivation nor asks for the passphrase.
+type KeystoreSigner struct {
+	path       string
+	address    common.Address
+	passphrase func() (string, error)
+
+	once sync.Once
+	key  *KeySigner
+	err  error
+}
+
+// OpenKeystore returns a signer for the keystore file, passphrase is called once the key has to be decrypted.
+func OpenKeystore(path string, passphrase func() (string, error)) (*KeystoreSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Address string `json:"address"`
+	}
+
+	if err := json.Unmarshal(keyJSON, &header); err != nil {
+		return nil, fmt.Errorf("invalid keystore %s: %w", path, err)
+	}
+
+	if !common.IsHexAddress(header.Address) {
+		return nil, fmt.Errorf("invalid keystore %s: no address", path)
+	}
+
+	return &KeystoreSigner{path: path, address: common.HexToAddress(header.Address), passphrase: passphrase}, nil
+}
+
+// Address returns the address the keystore file is declared for.
+func (s *KeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx decrypts the key the first time it is called and signs the transaction like `KeySigner.SignTx`.
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	s.once.Do(func() {
+		passphrase, err := s.passphrase()
+		if err != nil {
+			s.err = err
+			return
+		}
+
+		if s.key, s.err = FromKeystore(s.path, passphrase); s.err == nil && s.key.Address() != s.address {
+			s.err = fmt.Errorf("keystore %s holds the key of %s, not %s", s.path, s.key.Address().Hex(), s.address.Hex())
+		}
+	})
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.key.SignTx(tx, chainID)
+}
+
+// ReadPassphrase reads the passphrase from the given file, only a single trailing newline is stripped. When no file
+// is given the passphrase is prompted for on the terminal without echoing it.
+func ReadPassphrase(file, prompt string) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", ErrNoTerminal
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	passphrase, err := term.ReadPassword(fd)
+	if err != nil {
+		return "", err
+	}
+
+	return string(passphrase), nil
+}
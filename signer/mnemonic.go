@@ -0,0 +1,111 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath is the first account of the ethereum coin type, which is also what evmos uses for
+// its eth_secp256k1 keys
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// ErrInvalidMnemonic is returned for mnemonics with unknown words or a bad checksum
+var ErrInvalidMnemonic = errors.New("invalid bip-39 mnemonic")
+
+// errInvalidChildKey is returned for the (astronomically unlikely) derivation indexes bip-32 declares invalid
+var errInvalidChildKey = errors.New("derived key is invalid, use the next index")
+
+// FromMnemonic returns a signer for the key derived from a bip-39 mnemonic and optional bip-39 passphrase along the
+// bip-32 derivation path, `DefaultDerivationPath` is used when the path is empty.
+func FromMnemonic(mnemonic, passphrase, path string) (*KeySigner, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// the word list check above does not cover the checksum, seed creation does
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMnemonic, err)
+	}
+
+	key, err := deriveKey(seed, derivationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySigner(key), nil
+}
+
+// deriveKey derives the private key of the path from the seed following bip-32.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+
+	sum := hmacSHA512([]byte("Bitcoin seed"), seed)
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+
+	if key.Sign() == 0 || key.Cmp(n) >= 0 {
+		return nil, errInvalidChildKey
+	}
+
+	for _, index := range path {
+		var data []byte
+
+		if index >= 0x80000000 {
+			// hardened child, derived from the parent private key
+			data = append([]byte{0}, math.PaddedBigBytes(key, 32)...)
+		} else {
+			// normal child, derived from the compressed parent public key
+			parent, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+
+		data = binary.BigEndian.AppendUint32(data, index)
+		sum = hmacSHA512(chainCode, data)
+
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, errInvalidChildKey
+		}
+
+		key = tweak.Add(tweak, key).Mod(tweak, n)
+		if key.Sign() == 0 {
+			return nil, errInvalidChildKey
+		}
+
+		chainCode = sum[32:]
+	}
+
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,44 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeySigner signs transactions with an in memory private key, every other signer in this package
+// (keystore, mnemonic) resolves to one once the key is unlocked.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner returns a signer for the given private key.
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// FromHex returns a signer for a hex encoded private key, as exported by
+// `evmosd keys unsafe-export-eth-key`.
+func FromHex(hexKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySigner(key), nil
+}
+
+// Address returns the address of the account the signer signs for.
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs the transaction for the given chain id with the latest signer supported by go-ethereum, which
+// covers both legacy and dynamic fee transactions.
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
@@ -0,0 +1,203 @@
+package signer_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gopherine/evmos-conploy/signer"
+)
+
+var (
+	testKeyStr  = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+	testAddr    = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+	devMnemonic = "test test test test test test test test test test test junk"
+)
+
+func TestFromHex(t *testing.T) {
+	s, err := signer.FromHex(testKeyStr)
+	require.NoError(t, err)
+	assert.Equal(t, testAddr, s.Address())
+
+	_, err = signer.FromHex("invalid_key")
+	assert.Error(t, err)
+}
+
+func TestSignTx(t *testing.T) {
+	s, err := signer.FromHex(testKeyStr)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(9000)
+	subtests := []struct {
+		name string
+		tx   *types.Transaction
+	}{
+		{
+			name: "Legacy transaction",
+			tx:   types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1000)}),
+		},
+		{
+			name: "Dynamic fee transaction",
+			tx:   types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1000)}),
+		},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := s.SignTx(tt.tx, chainID)
+			require.NoError(t, err)
+
+			from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			require.NoError(t, err)
+			assert.Equal(t, testAddr, from)
+		})
+	}
+}
+
+func TestFromKeystore(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA(testKeyStr)
+	require.NoError(t, err)
+
+	// Encrypt the test key the same way geth's keystore does, with light scrypt params to keep the test fast.
+	key := &keystore.Key{Id: uuid.New(), Address: testAddr, PrivateKey: privateKey}
+
+	keyJSON, err := keystore.EncryptKey(key, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+
+	passFile := filepath.Join(dir, "pass.txt")
+	require.NoError(t, os.WriteFile(passFile, []byte("passphrase\n"), 0o600))
+
+	passphrase, err := signer.ReadPassphrase(passFile, "")
+	require.NoError(t, err)
+	assert.Equal(t, "passphrase", passphrase)
+
+	ks, err := signer.FromKeystore(keyFile, passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, testAddr, ks.Address())
+
+	_, err = signer.FromKeystore(keyFile, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	_, err = signer.FromKeystore(filepath.Join(dir, "missing.json"), passphrase)
+	assert.Error(t, err)
+}
+
+func TestOpenKeystore(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA(testKeyStr)
+	require.NoError(t, err)
+
+	key := &keystore.Key{Id: uuid.New(), Address: testAddr, PrivateKey: privateKey}
+
+	keyJSON, err := keystore.EncryptKey(key, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+
+	t.Run("Decrypts the key once when signing", func(t *testing.T) {
+		prompts := 0
+		ks, err := signer.OpenKeystore(keyFile, func() (string, error) {
+			prompts++
+			return "passphrase", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testAddr, ks.Address())
+		assert.Zero(t, prompts, "the address is read without the passphrase")
+
+		for i := 0; i < 2; i++ {
+			signed, err := ks.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1000)}), big.NewInt(9000))
+			require.NoError(t, err)
+
+			from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(9000)), signed)
+			require.NoError(t, err)
+			assert.Equal(t, testAddr, from)
+		}
+
+		assert.Equal(t, 1, prompts)
+	})
+
+	t.Run("Fails to sign with a wrong passphrase", func(t *testing.T) {
+		ks, err := signer.OpenKeystore(keyFile, func() (string, error) { return "wrong", nil })
+		require.NoError(t, err)
+
+		_, err = ks.SignTx(types.NewTx(&types.LegacyTx{Gas: 21000, GasPrice: big.NewInt(1000)}), big.NewInt(9000))
+		assert.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+
+	t.Run("Fails to sign without a passphrase", func(t *testing.T) {
+		ks, err := signer.OpenKeystore(keyFile, func() (string, error) { return "", signer.ErrNoTerminal })
+		require.NoError(t, err)
+
+		_, err = ks.SignTx(types.NewTx(&types.LegacyTx{Gas: 21000, GasPrice: big.NewInt(1000)}), big.NewInt(9000))
+		assert.ErrorIs(t, err, signer.ErrNoTerminal)
+	})
+
+	t.Run("Rejects files without an address", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "key.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{"version":3}`), 0o600))
+
+		_, err := signer.OpenKeystore(invalid, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestFromMnemonic(t *testing.T) {
+	subtests := []struct {
+		name     string
+		mnemonic string
+		path     string
+		want     common.Address
+		wantErr  error
+	}{
+		{
+			name:     "Default derivation path",
+			mnemonic: devMnemonic,
+			want:     common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		},
+		{
+			name:     "Custom derivation path",
+			mnemonic: devMnemonic,
+			path:     "m/44'/60'/0'/0/1",
+			want:     common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		},
+		{
+			name:     "Extra whitespace in mnemonic",
+			mnemonic: "  test test test test test test\ntest test test test test junk ",
+			want:     common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		},
+		{
+			name:     "Error bad checksum",
+			mnemonic: "test test test test test test test test test test test test",
+			wantErr:  signer.ErrInvalidMnemonic,
+		},
+	}
+
+	for _, tt := range subtests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := signer.FromMnemonic(tt.mnemonic, "", tt.path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Address())
+		})
+	}
+
+	_, err := signer.FromMnemonic(devMnemonic, "", "m/not/a/path")
+	assert.Error(t, err)
+}